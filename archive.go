@@ -0,0 +1,113 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// BlogArchive is the content of the archive pages. The "/blog/archive/" page
+// contains all years, a year page only its year and a month page only its
+// month.
+type BlogArchive struct {
+	Title string
+	Url   string
+	Years []BlogArchiveYear
+}
+
+type BlogArchiveYear struct {
+	Year   int
+	Url    string
+	Months []BlogArchiveMonth
+	Posts  []BlogPost
+}
+
+type BlogArchiveMonth struct {
+	Year  int
+	Month time.Month
+	Url   string
+	Posts []BlogPost
+}
+
+// archiveYears groups the posts by the year and month of their publish date in
+// the given location. The posts must be sorted by their publish date, so that
+// the years, months and posts are sorted, starting with the newest one.
+func archiveYears(posts []BlogPost, loc *time.Location) []BlogArchiveYear {
+	var years []BlogArchiveYear
+
+	for _, post := range posts {
+		publishedAt := post.PublishedAt.In(loc)
+
+		if len(years) == 0 || years[len(years)-1].Year != publishedAt.Year() {
+			years = append(years, BlogArchiveYear{
+				Year: publishedAt.Year(),
+				Url:  fmt.Sprintf("/blog/%d/", publishedAt.Year()),
+			})
+		}
+		year := &years[len(years)-1]
+		year.Posts = append(year.Posts, post)
+
+		if len(year.Months) == 0 || year.Months[len(year.Months)-1].Month != publishedAt.Month() {
+			year.Months = append(year.Months, BlogArchiveMonth{
+				Year:  publishedAt.Year(),
+				Month: publishedAt.Month(),
+				Url:   fmt.Sprintf("/blog/%d/%02d/", publishedAt.Year(), publishedAt.Month()),
+			})
+		}
+		month := &year.Months[len(year.Months)-1]
+		month.Posts = append(month.Posts, post)
+	}
+
+	return years
+}
+
+// buildArchive builds the archive pages and their feeds for the posts with the
+// given pool.
+func (b *Builder) buildArchive(p *pool, posts []BlogPost) {
+	site := b.site
+
+	archiveMetadata := site.metadata(site.Sections.Blog, "/blog/archive/")
+	archiveMetadata.Title = site.title("Archive", site.Sections.Blog.ItemTitle)
+
+	years := archiveYears(posts, site.location)
+
+	b.buildArchivePage(p, archiveMetadata, BlogArchive{Title: "Archive", Url: archiveMetadata.Url, Years: years}, posts)
+
+	for _, year := range years {
+		title := fmt.Sprintf("%d", year.Year)
+
+		metadata := archiveMetadata
+		metadata.Title = site.title(title, site.Sections.Blog.ItemTitle)
+		metadata.Description = fmt.Sprintf("Blog Posts from %s", title)
+		metadata.Url = year.Url
+
+		b.buildArchivePage(p, metadata, BlogArchive{Title: title, Url: year.Url, Years: []BlogArchiveYear{year}}, year.Posts)
+
+		for _, month := range year.Months {
+			title := fmt.Sprintf("%s %d", month.Month, month.Year)
+
+			metadata := archiveMetadata
+			metadata.Title = site.title(title, site.Sections.Blog.ItemTitle)
+			metadata.Description = fmt.Sprintf("Blog Posts from %s", title)
+			metadata.Url = month.Url
+
+			monthYear := year
+			monthYear.Months = []BlogArchiveMonth{month}
+			monthYear.Posts = month.Posts
+
+			b.buildArchivePage(p, metadata, BlogArchive{Title: title, Url: month.Url, Years: []BlogArchiveYear{monthYear}}, month.Posts)
+		}
+	}
+}
+
+func (b *Builder) buildArchivePage(p *pool, metadata Metadata, archive BlogArchive, posts []BlogPost) {
+	p.Go(func() {
+		b.errs.Add("templates/blog-archive.html", fmt.Sprintf("render %s", metadata.Url), b.buildTemplate("blog-archive", urlPath(metadata.Url), Data{
+			Metadata: metadata,
+			Content:  archive,
+		}))
+	})
+
+	p.Go(func() {
+		b.errs.Add("blog", fmt.Sprintf("feed %sfeed.xml", metadata.Url), b.buildRssFeed(urlPath(metadata.Url), metadata, posts))
+	})
+}
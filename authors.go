@@ -0,0 +1,225 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+)
+
+// authorsFile is the registry of the authors. It is optional, posts which do
+// not reference an author from the registry must set the "AuthorName",
+// "AuthorTitle" and "AuthorImage" fields in their front matter.
+const authorsFile = "blog/authors.yaml"
+
+// AuthorConfig is an author in the "blog/authors.yaml" file, where the authors
+// are listed by their id. The email is only used in the feeds, because RSS
+// requires an email address for the author of an item.
+type AuthorConfig struct {
+	Name  string       `yaml:"name"`
+	Title string       `yaml:"title"`
+	Image string       `yaml:"image"`
+	Bio   string       `yaml:"bio"`
+	Email string       `yaml:"email"`
+	Links []AuthorLink `yaml:"links"`
+}
+
+type AuthorLink struct {
+	Title string `yaml:"title"`
+	Url   string `yaml:"url"`
+}
+
+// BlogPostAuthor is an author of a blog post. Only authors from the registry
+// have an id and a page, other authors from the "AuthorName", "AuthorTitle"
+// and "AuthorImage" fields of the front matter only have a name, title and
+// image.
+type BlogPostAuthor struct {
+	ID    string
+	Name  string
+	Title string
+	Image string
+	Bio   string
+	Email string
+	Url   string
+	Links []AuthorLink
+}
+
+// rssAuthor returns the author in the format, which is required for the
+// "author" element of an RSS item, e.g. "rico@example.com (Rico Berger)". If
+// the author has no email address, an empty string is returned.
+func (a BlogPostAuthor) rssAuthor() string {
+	if a.Email == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s (%s)", a.Email, a.Name)
+}
+
+// BlogAuthor is a page of the "/blog/authors/<id>/" pages.
+type BlogAuthor struct {
+	Author BlogPostAuthor
+	Posts  []BlogPost
+	Pager  Pager
+}
+
+func newBlogPostAuthor(id string, config AuthorConfig) BlogPostAuthor {
+	return BlogPostAuthor{
+		ID:    id,
+		Name:  config.Name,
+		Title: config.Title,
+		Image: config.Image,
+		Bio:   config.Bio,
+		Email: config.Email,
+		Url:   fmt.Sprintf("/blog/authors/%s/", id),
+		Links: config.Links,
+	}
+}
+
+// authorNames returns the names of the given authors.
+func authorNames(authors []BlogPostAuthor) []string {
+	names := make([]string, 0, len(authors))
+	for _, author := range authors {
+		names = append(names, author.Name)
+	}
+	return names
+}
+
+// loadAuthors loads the authors from the "blog/authors.yaml" file. If the file
+// doesn't exist, the registry is empty.
+func (b *Builder) loadAuthors() {
+	b.authorConfigs = make(map[string]AuthorConfig)
+
+	content, err := fs.ReadFile(b.src, authorsFile)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			b.errs.Add(authorsFile, "read", err)
+		}
+		return
+	}
+
+	var configs map[string]AuthorConfig
+	if err := yaml.UnmarshalWithOptions(content, &configs, yaml.DisallowUnknownField()); err != nil {
+		b.errs.Add(authorsFile, "yaml", errors.New(yaml.FormatError(err, false, false)))
+		return
+	}
+
+	for _, id := range slices.Sorted(maps.Keys(configs)) {
+		if slugify(id) != id {
+			b.errs.Add(authorsFile, "authors", fmt.Errorf("author %q must be a slug, e.g. %q", id, slugify(id)))
+			continue
+		}
+		if configs[id].Name == "" {
+			b.errs.Add(authorsFile, "authors", fmt.Errorf("missing name of author %q", id))
+			continue
+		}
+
+		b.authorConfigs[id] = configs[id]
+	}
+}
+
+// resolveAuthors returns the authors of a post for its front matter. The
+// authors from the "Author" or "Authors" field must be in the registry. If both
+// fields are empty, the author from the "AuthorName", "AuthorTitle" and
+// "AuthorImage" fields is returned. When the registry contains an author with
+// this name, the post is also linked to the page of the author.
+func (b *Builder) resolveAuthors(frontMatter BlogPostFrontMatter) ([]BlogPostAuthor, error) {
+	ids := frontMatter.Authors
+	if frontMatter.Author != "" {
+		ids = []string{frontMatter.Author}
+	}
+
+	if len(ids) == 0 {
+		author := BlogPostAuthor{}
+		if id := b.authorID(frontMatter.AuthorName); id != "" {
+			author = newBlogPostAuthor(id, b.authorConfigs[id])
+		}
+
+		author.Name = frontMatter.AuthorName
+		author.Title = frontMatter.AuthorTitle
+		author.Image = frontMatter.AuthorImage
+
+		return []BlogPostAuthor{author}, nil
+	}
+
+	var authors []BlogPostAuthor
+	for _, id := range ids {
+		config, ok := b.authorConfigs[id]
+		if !ok {
+			return nil, fmt.Errorf("unknown author %q: must be defined in %s", id, authorsFile)
+		}
+
+		if !slices.ContainsFunc(authors, func(a BlogPostAuthor) bool { return a.ID == id }) {
+			authors = append(authors, newBlogPostAuthor(id, config))
+		}
+	}
+
+	return authors, nil
+}
+
+// authorID returns the id of the author with the given name from the registry.
+// If there is no such author, an empty string is returned.
+func (b *Builder) authorID(name string) string {
+	for _, id := range slices.Sorted(maps.Keys(b.authorConfigs)) {
+		if strings.EqualFold(b.authorConfigs[id].Name, name) {
+			return id
+		}
+	}
+	return ""
+}
+
+// postsByAuthor returns all posts, which were written by the author with the
+// given id.
+func postsByAuthor(posts []BlogPost, id string) []BlogPost {
+	var authorPosts []BlogPost
+	for _, post := range posts {
+		if slices.ContainsFunc(post.Authors, func(a BlogPostAuthor) bool { return a.ID == id }) {
+			authorPosts = append(authorPosts, post)
+		}
+	}
+	return authorPosts
+}
+
+// buildAuthors builds the paginated pages and the feed for every author from
+// the registry with the given pool. The posts must be sorted by their publish
+// date.
+func (b *Builder) buildAuthors(p *pool, posts []BlogPost) {
+	site := b.site
+
+	for _, id := range slices.Sorted(maps.Keys(b.authorConfigs)) {
+		author := newBlogPostAuthor(id, b.authorConfigs[id])
+
+		authorPosts := postsByAuthor(posts, id)
+
+		authorMetadata := site.metadata(site.Sections.Blog, author.Url)
+		authorMetadata.Title = site.title(author.Name, site.Sections.Blog.ItemTitle)
+		if author.Bio != "" {
+			authorMetadata.Description = author.Bio
+		}
+
+		for _, page := range paginate(authorPosts, site.Pagination.Authors, authorMetadata.Url) {
+			metadata := authorMetadata
+			metadata.Title = site.title(append(pageTitle(page.Pager.Page, author.Name), site.Sections.Blog.ItemTitle)...)
+			metadata.Url = page.Url
+			metadata.PrevUrl = page.Pager.PrevUrl
+			metadata.NextUrl = page.Pager.NextUrl
+
+			p.Go(func() {
+				b.errs.Add("templates/blog-author.html", fmt.Sprintf("render %s", metadata.Url), b.buildTemplate("blog-author", urlPath(page.Url), Data{
+					Metadata: metadata,
+					Content: BlogAuthor{
+						Author: author,
+						Posts:  page.Posts,
+						Pager:  page.Pager,
+					},
+				}))
+			})
+		}
+
+		p.Go(func() {
+			b.errs.Add(authorsFile, fmt.Sprintf("feed %sfeed.xml", authorMetadata.Url), b.buildRssFeed(urlPath(authorMetadata.Url), authorMetadata, authorPosts))
+		})
+	}
+}
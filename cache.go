@@ -0,0 +1,252 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Cache stores the content hash of the inputs for every output of the build in
+// a manifest file in the cache directory. When the inputs of an output didn't
+// change since the last build and the output still exists, the output is not
+// built again.
+//
+// The inputs of a page are the templates and the data which is passed to the
+// templates, so that a page is also rebuilt when the data it depends on
+// changes, e.g. a tag page when the tags of a post change. The hash of the
+// generator binary is part of every hash, so that all outputs are rebuilt when
+// the generator changes.
+type Cache struct {
+	path    string
+	outDir  string
+	out     Output
+	version string
+
+	mu      sync.Mutex
+	old     map[string]string
+	outputs map[string]string
+}
+
+// cacheManifest is the content of the manifest file. The outputs are only
+// used, when the manifest was written for the same output directory, so that
+// building into another directory never removes files of the old directory.
+type cacheManifest struct {
+	Out     string            `json:"out"`
+	Outputs map[string]string `json:"outputs"`
+}
+
+// loadCache loads the manifest for the "outDir" directory from the given cache
+// directory. The outputs are checked and removed in "out", which writes into
+// "outDir". If "force" is set, the existing manifest is ignored, so that all
+// outputs are built again.
+func loadCache(dir, outDir string, out Output, force bool) (*Cache, error) {
+	version, err := executableHash()
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Cache{
+		path:    filepath.Join(dir, "manifest.json"),
+		outDir:  outDir,
+		out:     out,
+		version: version,
+		old:     make(map[string]string),
+		outputs: make(map[string]string),
+	}
+
+	if force {
+		return c, nil
+	}
+
+	content, err := os.ReadFile(c.path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return c, nil
+		}
+		return nil, err
+	}
+
+	var manifest cacheManifest
+	if err := json.Unmarshal(content, &manifest); err != nil {
+		return nil, err
+	}
+
+	if manifest.Out == outDir && manifest.Outputs != nil {
+		c.old = manifest.Outputs
+	}
+
+	return c, nil
+}
+
+// Build runs the build function for the output, when the hash of the inputs
+// changed since the last build or the output doesn't exist anymore. If the
+// cache is nil, the build function is always run.
+func (c *Cache) Build(output, hash string, build func() error) error {
+	if c == nil {
+		return build()
+	}
+
+	hash, err := hashOf(c.version, hash)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	oldHash, ok := c.old[output]
+	c.mu.Unlock()
+
+	if ok && oldHash == hash {
+		if _, err := c.out.Stat(output); err == nil {
+			c.set(output, hash)
+			return nil
+		}
+	}
+
+	if err := build(); err != nil {
+		return err
+	}
+
+	c.set(output, hash)
+	return nil
+}
+
+func (c *Cache) set(output, hash string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.outputs[output] = hash
+}
+
+// Save writes the manifest to the cache directory. When the build was
+// successful, all outputs of the last build which were not built again are
+// removed, e.g. the page of a deleted blog post. When the build failed, not all
+// outputs were built, so that we keep the outputs of the last build in the
+// manifest and don't remove anything.
+func (c *Cache) Save(success bool) error {
+	if c == nil {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for output, hash := range c.old {
+		if _, ok := c.outputs[output]; ok {
+			continue
+		}
+
+		if success {
+			// The output can be a directory of an older build, which now
+			// contains outputs of this build, e.g. the assets, which were
+			// copied as a single output before.
+			if c.hasOutputsIn(output) {
+				continue
+			}
+
+			if err := c.out.Remove(output); err != nil {
+				return err
+			}
+		} else {
+			c.outputs[output] = hash
+		}
+	}
+
+	content, err := json.MarshalIndent(cacheManifest{Out: c.outDir, Outputs: c.outputs}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(c.path), os.ModePerm); err != nil {
+		return err
+	}
+
+	return os.WriteFile(c.path, content, 0600)
+}
+
+// hasOutputsIn returns true, when an output of this build is in the given
+// directory.
+func (c *Cache) hasOutputsIn(dir string) bool {
+	for output := range c.outputs {
+		if strings.HasPrefix(output, dir+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// hashOf returns the hex encoded SHA-256 hash of the JSON encoding of all
+// parts.
+func hashOf(parts ...any) (string, error) {
+	h := sha256.New()
+	enc := json.NewEncoder(h)
+
+	for _, part := range parts {
+		if err := enc.Encode(part); err != nil {
+			return "", err
+		}
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// hashFiles returns the hash of the given files in the file system. Files which
+// do not exist are ignored.
+func hashFiles(fsys fs.FS, paths ...string) (string, error) {
+	h := sha256.New()
+
+	for _, path := range paths {
+		f, err := fsys.Open(path)
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			return "", err
+		}
+
+		_, _ = io.WriteString(h, path+"\x00")
+		_, err = io.Copy(h, f)
+		f.Close()
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// hashDir returns the hash of the names and contents of all files in the
+// directory of the file system.
+func hashDir(fsys fs.FS, dir string) (string, error) {
+	var paths []string
+
+	if err := fs.WalkDir(fsys, dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			paths = append(paths, path)
+		}
+		return nil
+	}); err != nil {
+		return "", err
+	}
+
+	return hashFiles(fsys, paths...)
+}
+
+// executableHash returns the hash of the running generator binary.
+func executableHash() (string, error) {
+	path, err := os.Executable()
+	if err != nil {
+		return "", err
+	}
+
+	return hashFiles(os.DirFS(filepath.Dir(path)), filepath.Base(path))
+}
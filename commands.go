@@ -0,0 +1,359 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+const usage = `Usage: generator <command> [flags]
+
+Commands:
+  build                     Build the site. This is the default command.
+  serve                     Start a local server to preview the site.
+  new post <slug>           Create a new blog post.
+  new cheat-sheet <id>      Create a new cheat sheet.
+  check                     Validate the site without writing any output.
+  list                      List all blog posts and cheat sheets.
+
+Run "generator <command> -h" for the flags of a command.
+`
+
+// slugRegexp is the allowed format for the ids of blog posts and cheat sheets,
+// which are also used as directory names and in urls.
+var slugRegexp = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// run runs the command from the given arguments. Without a command the site is
+// built, so that the generator can still be used without arguments or with
+// only the flags of the build, e.g. "generator -drafts".
+func run(args []string) error {
+	if len(args) == 0 {
+		return runBuildCommand(args)
+	}
+
+	switch args[0] {
+	case "build":
+		return runBuildCommand(args[1:])
+	case "serve":
+		return runServeCommand(args[1:])
+	case "new":
+		return runNewCommand(args[1:])
+	case "check":
+		return runCheckCommand(args[1:])
+	case "list":
+		return runListCommand(args[1:])
+	case "help", "-h", "-help", "--help":
+		fmt.Fprint(os.Stdout, usage)
+		return nil
+	default:
+		if strings.HasPrefix(args[0], "-") {
+			for _, arg := range args {
+				if name, _, _ := strings.Cut(strings.TrimLeft(arg, "-"), "="); name == "serve" {
+					return errors.New(`the "-serve" flag was replaced by the "serve" command, e.g. "generator serve -watch"`)
+				}
+			}
+			return runBuildCommand(args)
+		}
+
+		fmt.Fprint(os.Stderr, usage)
+		return fmt.Errorf("unknown command %q", args[0])
+	}
+}
+
+// sourceFlags adds the flags for the source directory and the site
+// configuration, which are used by all commands.
+func sourceFlags(fs *flag.FlagSet, opts *BuildOptions) {
+	fs.StringVar(&opts.Src, "src", ".", "Source directory with the \"blog\", \"cheat-sheets\" and \"templates\" directories.")
+	fs.StringVar(&opts.Config, "config", "site.yaml", "Path to the site configuration file, relative to the source directory.")
+}
+
+// buildFlags adds the flags for a build to the flag set.
+func buildFlags(fs *flag.FlagSet, opts *BuildOptions) {
+	sourceFlags(fs, opts)
+	fs.StringVar(&opts.Out, "out", "./dist", "Output directory for the generated site.")
+	fs.BoolVar(&opts.KeepGoing, "keep-going", false, "Continue building the other sections, when a section fails.")
+	fs.IntVar(&opts.Jobs, "jobs", runtime.NumCPU(), "Number of pages, which are built concurrently.")
+	fs.StringVar(&opts.CacheDir, "cache-dir", ".cache", "Directory for the build cache.")
+	fs.BoolVar(&opts.Force, "force", false, "Ignore the build cache and build all pages.")
+	fs.BoolVar(&opts.Drafts, "drafts", false, "Include draft blog posts.")
+	fs.BoolVar(&opts.Future, "future", false, "Include blog posts with a publish date in the future.")
+}
+
+func runBuildCommand(args []string) error {
+	var opts BuildOptions
+
+	fs := flag.NewFlagSet("build", flag.ContinueOnError)
+	buildFlags(fs, &opts)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if err := runBuild(opts); err != nil {
+		return errors.New(formatBuildError(err))
+	}
+
+	return nil
+}
+
+func runServeCommand(args []string) error {
+	var opts BuildOptions
+	var address string
+	var port int
+	var watch bool
+
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	buildFlags(fs, &opts)
+	fs.StringVar(&address, "address", "", "Address the server listens on. If empty, the server listens on all addresses.")
+	fs.IntVar(&port, "port", 9999, "Port the server listens on.")
+	fs.BoolVar(&watch, "watch", false, "Rebuild the site on changes and reload the browser.")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	// Without "-watch" the site isn't built, so that the flags for the build
+	// would be ignored.
+	if !watch {
+		var buildOnly []string
+		fs.Visit(func(f *flag.Flag) {
+			if f.Name != "out" && f.Name != "address" && f.Name != "port" && f.Name != "watch" {
+				buildOnly = append(buildOnly, fmt.Sprintf("-%s", f.Name))
+			}
+		})
+		if len(buildOnly) > 0 {
+			return fmt.Errorf("%s can only be used together with -watch", strings.Join(buildOnly, ", "))
+		}
+	}
+
+	return runServer(net.JoinHostPort(address, strconv.Itoa(port)), opts.Out, watch, opts)
+}
+
+func runNewCommand(args []string) error {
+	var opts BuildOptions
+
+	fs := flag.NewFlagSet("new", flag.ContinueOnError)
+	sourceFlags(fs, &opts)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: generator new post <slug>\n       generator new cheat-sheet <id>\n\nFlags:\n")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return errors.New("expected a type and an id")
+	}
+
+	kind, id := fs.Arg(0), fs.Arg(1)
+	if !slugRegexp.MatchString(id) {
+		return fmt.Errorf("invalid id %q: only lowercase letters, digits and dashes are allowed", id)
+	}
+
+	builder, err := newBuilder(opts)
+	if err != nil {
+		return err
+	}
+
+	switch kind {
+	case "post":
+		return scaffold(filepath.Join(opts.Src, "blog", id), fmt.Sprintf("%s.md", id), builder.newBlogPost(id))
+	case "cheat-sheet":
+		return scaffold(filepath.Join(opts.Src, "cheat-sheets", id), fmt.Sprintf("%s.yaml", id), builder.newCheatSheet(id))
+	default:
+		fs.Usage()
+		return fmt.Errorf("unknown type %q", kind)
+	}
+}
+
+// scaffold creates the "<dir>/<file>" file with the given content and an empty
+// "assets" directory next to it. It fails when the directory already exists, so
+// that existing content is never overwritten.
+func scaffold(dir, file, content string) error {
+	if ok, err := exists(dir); err != nil {
+		return err
+	} else if ok {
+		return fmt.Errorf("%s already exists", dir)
+	}
+
+	if err := os.MkdirAll(filepath.Join(dir, "assets"), os.ModePerm); err != nil {
+		return err
+	}
+
+	path := filepath.Join(dir, file)
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stdout, "Created %s\n", path)
+	return nil
+}
+
+// newBlogPost returns the content for a new blog post. The post is created as
+// draft, so that it isn't published before it is finished. If the author of
+// the site is in the "blog/authors.yaml" file, the post references the author
+// by its id.
+func (b *Builder) newBlogPost(id string) string {
+	b.loadAuthors()
+
+	author := fmt.Sprintf("AuthorName: %s\nAuthorTitle: %s\nAuthorImage: %s", b.site.Author, b.site.AuthorTitle, b.site.AuthorImage)
+	if authorID := b.authorID(b.site.Author); authorID != "" {
+		author = fmt.Sprintf("Author: %s", authorID)
+	}
+
+	return fmt.Sprintf(`---
+Title: %q
+Description: >-
+  TODO
+%s
+PublishedAt: %s
+Tags:
+  - TODO
+Draft: true
+---
+
+TODO
+`, titleFromSlug(id), author, b.now.In(b.site.location).Format(publishedAtLayout))
+}
+
+// newCheatSheet returns the content for a new cheat sheet.
+func (b *Builder) newCheatSheet(id string) string {
+	return fmt.Sprintf(`---
+title: %q
+description: >-
+  TODO
+author: %s
+keywords:
+  - %s
+pages:
+  - title: %q
+    columns: 4
+    sections:
+      - title: General
+        items:
+          - TODO
+`, titleFromSlug(id), b.site.Author, id, titleFromSlug(id))
+}
+
+// titleFromSlug returns a title for the given slug, e.g. "My Dotfiles" for
+// "my-dotfiles".
+func titleFromSlug(slug string) string {
+	words := strings.Split(slug, "-")
+	for i, word := range words {
+		words[i] = strings.ToUpper(word[:1]) + word[1:]
+	}
+	return strings.Join(words, " ")
+}
+
+// runCheckCommand validates the site configuration, all templates, blog posts
+// and cheat sheets, without writing any output. The site is built into memory,
+// so that the check fails for every error, which also fails the build.
+func runCheckCommand(args []string) error {
+	var opts BuildOptions
+
+	fs := flag.NewFlagSet("check", flag.ContinueOnError)
+	sourceFlags(fs, &opts)
+	fs.IntVar(&opts.Jobs, "jobs", runtime.NumCPU(), "Number of pages, which are checked concurrently.")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	opts.KeepGoing = true
+
+	builder, err := newBuilder(opts)
+	if err != nil {
+		return err
+	}
+	builder.out = newMemOutput()
+
+	if err := builder.check(); err != nil {
+		return errors.New(formatBuildError(err))
+	}
+
+	fmt.Fprintln(os.Stdout, "Check passed")
+	return nil
+}
+
+// check parses all templates, also the ones which are not used by the build,
+// and builds the site. It returns all errors.
+func (b *Builder) check() error {
+	templates, err := fs.Glob(b.src, "templates/*.html")
+	if err != nil {
+		return err
+	}
+
+	for _, file := range templates {
+		tmpl := strings.TrimSuffix(path.Base(file), ".html")
+		if tmpl == "base" {
+			continue
+		}
+
+		_, err := b.parseTemplate(tmpl)
+		b.errs.Add(fmt.Sprintf("templates/%s.html", tmpl), "parse", err)
+	}
+
+	return b.build()
+}
+
+// runListCommand prints all blog posts and cheat sheets.
+func runListCommand(args []string) error {
+	var opts BuildOptions
+
+	fs := flag.NewFlagSet("list", flag.ContinueOnError)
+	sourceFlags(fs, &opts)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	builder, err := newBuilder(opts)
+	if err != nil {
+		return err
+	}
+
+	posts := builder.readBlogPosts()
+	sortBlogPosts(posts)
+	cheatSheets := builder.readCheatSheets()
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(w, "PUBLISHED\tSTATUS\tPOST\tTAGS")
+	for _, post := range posts {
+		status := "published"
+		if post.Draft {
+			status = "draft"
+		} else if post.Scheduled {
+			status = "scheduled"
+		} else if post.Unlisted {
+			status = "unlisted"
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", post.PublishedAt.Format(time.DateOnly), status, post.ID, strings.Join(tagNames(post.Tags), ", "))
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "CHEAT SHEET\tTITLE")
+	for _, cheatSheet := range cheatSheets {
+		fmt.Fprintf(w, "%s\t%s\n", cheatSheet.ID, cheatSheet.Title)
+	}
+
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if err := builder.errs.Err(); err != nil {
+		return errors.New(formatBuildError(err))
+	}
+
+	return nil
+}
@@ -0,0 +1,244 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// buildTime returns the time of the build, which is used for the dates in the
+// feeds and the sitemap, which can not be derived from the content. If the
+// "SOURCE_DATE_EPOCH" environment variable is set, its value is used instead of
+// the current time, so that two builds of the same commit are byte-identical.
+// It is not used to decide which posts are scheduled.
+//
+// See https://reproducible-builds.org/specs/source-date-epoch/
+func buildTime() (time.Time, error) {
+	epoch, ok := os.LookupEnv("SOURCE_DATE_EPOCH")
+	if !ok || epoch == "" {
+		return time.Now(), nil
+	}
+
+	seconds, err := strconv.ParseInt(epoch, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid SOURCE_DATE_EPOCH %q: %w", epoch, err)
+	}
+
+	return time.Unix(seconds, 0).UTC(), nil
+}
+
+// gitModTimes returns the time of the last commit for all files in the given
+// directory, with the paths relative to the directory. If the directory is not
+// part of a git repository, nil is returned.
+func gitModTimes(dir string) map[string]time.Time {
+	cmd := exec.Command("git", "-C", dir, "log", "--format=%x00%ct", "--name-only", "--relative", "--", ".")
+
+	out, err := cmd.Output()
+	if err != nil {
+		slog.Debug("Failed to get modification times from git", slog.Any("error", err))
+		return nil
+	}
+
+	modTimes := make(map[string]time.Time)
+
+	var commitTime time.Time
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if seconds, ok := strings.CutPrefix(line, "\x00"); ok {
+			unix, err := strconv.ParseInt(seconds, 10, 64)
+			if err != nil {
+				return nil
+			}
+			commitTime = time.Unix(unix, 0).UTC()
+			continue
+		}
+
+		if line == "" {
+			continue
+		}
+
+		if modTime, ok := modTimes[line]; !ok || commitTime.After(modTime) {
+			modTimes[line] = commitTime
+		}
+	}
+
+	return modTimes
+}
+
+// BlogPostRevision is a commit, which changed a blog post.
+type BlogPostRevision struct {
+	Date    time.Time
+	Subject string
+}
+
+// gitRevisions returns the commits, which changed the given file or directory,
+// starting with the newest commit. The name must be relative to the directory.
+// If the directory is not part of a git repository, nil is returned.
+func gitRevisions(dir, name string) []BlogPostRevision {
+	cmd := exec.Command("git", "-C", dir, "log", "--format=%ct%x00%s", "--", name)
+
+	out, err := cmd.Output()
+	if err != nil {
+		slog.Debug("Failed to get revisions from git", slog.String("name", name), slog.Any("error", err))
+		return nil
+	}
+
+	var revisions []BlogPostRevision
+
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		seconds, subject, ok := strings.Cut(scanner.Text(), "\x00")
+		if !ok {
+			continue
+		}
+
+		unix, err := strconv.ParseInt(seconds, 10, 64)
+		if err != nil {
+			return nil
+		}
+
+		revisions = append(revisions, BlogPostRevision{
+			Date:    time.Unix(unix, 0).UTC(),
+			Subject: subject,
+		})
+	}
+
+	return revisions
+}
+
+// gitContentModTime returns the time of the last commit, which changed the
+// content of the blog post in the given directory. A commit, which only changes
+// the front matter of the post file, e.g. to fix a tag or a typo in the
+// description, is skipped. The names must be relative to the directory. If no
+// commit changed the content, the zero time is returned.
+func gitContentModTime(dir, name, file string) time.Time {
+	cmd := exec.Command("git", "-C", dir, "log", "--format=%x00%H %ct", "--name-only", "--relative", "--", name)
+
+	out, err := cmd.Output()
+	if err != nil {
+		slog.Debug("Failed to get modification time from git", slog.String("name", name), slog.Any("error", err))
+		return time.Time{}
+	}
+
+	var commit string
+	var commitTime time.Time
+	var files []string
+
+	// changed reports if the last parsed commit changed the content, which is
+	// the case if it changed another file than the post file or the body of the
+	// post file.
+	changed := func() bool {
+		if commit == "" {
+			return false
+		}
+		if slices.ContainsFunc(files, func(f string) bool { return f != file }) {
+			return true
+		}
+
+		body, ok := gitPostBody(dir, commit, file)
+		previousBody, previousOk := gitPostBody(dir, commit+"^", file)
+		return ok != previousOk || !bytes.Equal(body, previousBody)
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if value, ok := strings.CutPrefix(line, "\x00"); ok {
+			if changed() {
+				return commitTime
+			}
+
+			hash, seconds, _ := strings.Cut(value, " ")
+			unix, err := strconv.ParseInt(seconds, 10, 64)
+			if err != nil {
+				return time.Time{}
+			}
+
+			commit, commitTime, files = hash, time.Unix(unix, 0).UTC(), nil
+			continue
+		}
+
+		if line != "" {
+			files = append(files, line)
+		}
+	}
+
+	if changed() {
+		return commitTime
+	}
+	return time.Time{}
+}
+
+// gitPostBody returns the body of the post file without its front matter at
+// the given revision. If the file doesn't exist at the revision, false is
+// returned.
+func gitPostBody(dir, rev, file string) ([]byte, bool) {
+	content, err := exec.Command("git", "-C", dir, "show", fmt.Sprintf("%s:./%s", rev, file)).Output()
+	if err != nil {
+		return nil, false
+	}
+
+	_, body, err := splitFrontMatter(content)
+	if err != nil {
+		return content, true
+	}
+	return body, true
+}
+
+// lastModified returns the time of the last commit, which changed one of the
+// given files or a file in one of the given directories. If none of the files
+// was committed, the zero time is returned.
+func (b *Builder) lastModified(names ...string) time.Time {
+	b.modTimesOnce.Do(func() {
+		if b.gitDir != "" {
+			b.modTimes = gitModTimes(b.gitDir)
+		}
+	})
+
+	var last time.Time
+	for file, modTime := range b.modTimes {
+		for _, name := range names {
+			if (file == name || strings.HasPrefix(file, name+"/")) && modTime.After(last) {
+				last = modTime
+			}
+		}
+	}
+
+	return last
+}
+
+// newestPost returns the publish date of the newest post. The posts must be
+// sorted by their publish date. If there are no posts, the zero time is
+// returned.
+func newestPost(posts []BlogPost) time.Time {
+	if len(posts) == 0 {
+		return time.Time{}
+	}
+	return posts[0].PublishedAt
+}
+
+// latest returns the latest of the given times. If all times are zero, the
+// fallback is returned.
+func latest(fallback time.Time, times ...time.Time) time.Time {
+	var last time.Time
+	for _, t := range times {
+		if t.After(last) {
+			last = t
+		}
+	}
+
+	if last.IsZero() {
+		return fallback
+	}
+	return last
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestGitContentModTime(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	dir := t.TempDir()
+	post := "blog/test/test.md"
+
+	git := func(date string, args ...string) {
+		t.Helper()
+
+		cmd := exec.Command("git", append([]string{"-C", dir, "-c", "commit.gpgsign=false"}, args...)...)
+		cmd.Env = append(os.Environ(), "GIT_CONFIG_GLOBAL=/dev/null", "GIT_AUTHOR_NAME=Test", "GIT_AUTHOR_EMAIL=test@example.com", "GIT_COMMITTER_NAME=Test", "GIT_COMMITTER_EMAIL=test@example.com", "GIT_COMMITTER_DATE="+date)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v: %s", args, err, out)
+		}
+	}
+
+	commit := func(day int, files map[string]string) {
+		t.Helper()
+
+		for name, content := range files {
+			if err := os.MkdirAll(filepath.Join(dir, filepath.Dir(name)), 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		date := fmt.Sprintf("2025-01-%02dT12:00:00Z", day)
+		git(date, "add", "-A")
+		git(date, "commit", "-q", "-m", date)
+	}
+
+	modTime := func() time.Time {
+		return gitContentModTime(dir, "blog/test", post)
+	}
+
+	git("", "init", "-q")
+
+	if got := modTime(); !got.IsZero() {
+		t.Errorf("expected the zero time without commits, got %s", got)
+	}
+
+	commit(1, map[string]string{post: "---\nTitle: Test\n---\n\nBody\n"})
+	if expected := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC); !modTime().Equal(expected) {
+		t.Errorf("expected %s for the first commit, got %s", expected, modTime())
+	}
+
+	commit(2, map[string]string{post: "---\nTitle: Test\n---\n\nChanged body\n"})
+	commit(3, map[string]string{post: "---\nTitle: Changed title\n---\n\nChanged body\n"})
+	if expected := time.Date(2025, 1, 2, 12, 0, 0, 0, time.UTC); !modTime().Equal(expected) {
+		t.Errorf("expected %s, because only the front matter was changed, got %s", expected, modTime())
+	}
+
+	commit(4, map[string]string{"blog/test/image.png": "image"})
+	if expected := time.Date(2025, 1, 4, 12, 0, 0, 0, time.UTC); !modTime().Equal(expected) {
+		t.Errorf("expected %s for a changed asset, got %s", expected, modTime())
+	}
+
+	commit(5, map[string]string{"blog/other/other.md": "---\nTitle: Other\n---\n"})
+	if expected := time.Date(2025, 1, 4, 12, 0, 0, 0, time.UTC); !modTime().Equal(expected) {
+		t.Errorf("expected %s, because another post was changed, got %s", expected, modTime())
+	}
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// BuildError is an error which occurred in a phase of the build. The file is
+// the source file which caused the error, e.g. the markdown file of a blog post
+// or the template which could not be rendered.
+type BuildError struct {
+	File  string
+	Phase string
+	Err   error
+}
+
+func (e *BuildError) Error() string {
+	if e.File == "" {
+		return fmt.Sprintf("%s: %s", e.Phase, e.Err.Error())
+	}
+	return fmt.Sprintf("%s: %s: %s", e.File, e.Phase, e.Err.Error())
+}
+
+func (e *BuildError) Unwrap() error {
+	return e.Err
+}
+
+// BuildErrors collects all errors of a build, so that we can report all of
+// them at the end of the build instead of stopping at the first one. It is safe
+// to add errors from multiple goroutines.
+type BuildErrors struct {
+	mu   sync.Mutex
+	errs []*BuildError
+}
+
+// Add adds the error for the given file and phase. If the error is nil, it is
+// ignored, so that the result of a function can be passed directly.
+func (e *BuildErrors) Add(file, phase string, err error) {
+	if err == nil {
+		return
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	var buildErr *BuildError
+	if errors.As(err, &buildErr) {
+		e.errs = append(e.errs, buildErr)
+		return
+	}
+
+	e.errs = append(e.errs, &BuildError{File: file, Phase: phase, Err: err})
+}
+
+func (e *BuildErrors) Len() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return len(e.errs)
+}
+
+// Err returns nil when no errors were collected, so that the caller can check
+// the result of the build as for every other error.
+func (e *BuildErrors) Err() error {
+	if e.Len() == 0 {
+		return nil
+	}
+	return e
+}
+
+// Error returns all errors sorted by file, so that the report is the same for
+// every build, regardless of the order in which the errors were added.
+func (e *BuildErrors) Error() string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	errs := slices.Clone(e.errs)
+	slices.SortStableFunc(errs, func(a, b *BuildError) int {
+		return strings.Compare(a.File, b.File)
+	})
+
+	var lines []string
+	for _, err := range errs {
+		lines = append(lines, err.Error())
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (e *BuildErrors) Unwrap() []error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	var errs []error
+	for _, err := range e.errs {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+// formatBuildError formats the error of a build for the user. If the error
+// contains the errors of the build, the number of errors is added, so that
+// the user knows how many problems must be fixed.
+func formatBuildError(err error) string {
+	var buildErrs *BuildErrors
+	if errors.As(err, &buildErrs) {
+		return fmt.Sprintf("Build failed with %d error(s):\n%s", buildErrs.Len(), err.Error())
+	}
+
+	return fmt.Sprintf("Build failed: %s", err.Error())
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/yuin/goldmark/ast"
+)
+
+// moreMarker separates the excerpt of a post from the rest of its content. It
+// must be in its own paragraph, e.g. after the introduction of the post.
+const moreMarker = "<!--more-->"
+
+const (
+	// feedContentFull adds the full content of the posts to the feeds.
+	feedContentFull = "full"
+	// feedContentExcerpt adds only the excerpt of the posts to the feeds. Posts
+	// without an excerpt are added with their full content.
+	feedContentExcerpt = "excerpt"
+)
+
+// excerpt returns the rendered html before the more marker. If the markdown
+// document doesn't contain the marker as a top level block, an empty string
+// is returned.
+func excerpt(doc ast.Node, source []byte, content string) string {
+	for node := doc.FirstChild(); node != nil; node = node.NextSibling() {
+		block, ok := node.(*ast.HTMLBlock)
+		if !ok {
+			continue
+		}
+
+		var value bytes.Buffer
+		for i := range block.Lines().Len() {
+			line := block.Lines().At(i)
+			value.Write(line.Value(source))
+		}
+
+		if strings.TrimSpace(value.String()) == moreMarker {
+			before, _, _ := strings.Cut(content, moreMarker)
+			return strings.TrimSpace(before)
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/renderer/html"
+)
+
+func TestExcerpt(t *testing.T) {
+	md := goldmark.New(goldmark.WithRendererOptions(html.WithUnsafe()))
+
+	for _, tt := range []struct {
+		name    string
+		source  string
+		excerpt string
+	}{
+		{name: "marker after the introduction", source: "Intro *text*.\n\n<!--more-->\n\nMore.\n", excerpt: "<p>Intro <em>text</em>.</p>"},
+		{name: "marker with whitespace", source: "Intro.\n\n  <!--more-->  \n\nMore.\n", excerpt: "<p>Intro.</p>"},
+		{name: "marker at the beginning", source: "<!--more-->\n\nMore.\n", excerpt: ""},
+		{name: "without marker", source: "Intro.\n\nMore.\n", excerpt: ""},
+		{name: "marker inline", source: "Intro <!--more--> text.\n", excerpt: ""},
+		{name: "marker in a code block", source: "Intro.\n\n```html\n<!--more-->\n```\n\nMore.\n", excerpt: ""},
+		{name: "marker in a code block before the marker", source: "```html\n<!--more-->\n```\n\n<!--more-->\n\nMore.\n", excerpt: "<pre><code class=\"language-html\">&lt;!--more--&gt;\n</code></pre>"},
+		{name: "marker in an html block", source: "<div>\n<!--more-->\n</div>\n\nMore.\n", excerpt: ""},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			doc, err := renderMarkdown(md, newHeadingIDs(), []byte(tt.source), &buf)
+			if err != nil {
+				t.Fatalf("failed to render markdown: %v", err)
+			}
+
+			if result := excerpt(doc, []byte(tt.source), buf.String()); result != tt.excerpt {
+				t.Errorf("expected %q, got %q", tt.excerpt, result)
+			}
+		})
+	}
+}
@@ -0,0 +1,220 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/goccy/go-yaml"
+)
+
+// publishedAtLayout is the layout of the "PublishedAt" and "UpdatedAt" fields,
+// which was used before RFC 3339 was supported. Dates in this layout are parsed in the
+// timezone from the site configuration.
+const publishedAtLayout = "2006-01-02 15:04:05"
+
+// BlogPostFrontMatter is the front matter of a blog post. The "Title",
+// "Description", "PublishedAt" and "Tags" fields are required, all other
+// fields are optional.
+//
+// The authors are set via the "Author" or "Authors" field with ids from the
+// "blog/authors.yaml" file, or via the "AuthorName", "AuthorTitle" and
+// "AuthorImage" fields, which are only required when no id is set. The url of
+// the post is set via the site configuration and can be changed with the
+// "Slug" or "Permalink" field. The "Aliases" are old urls of the post, which
+// are redirected to the post. If the post was published on another site
+// first, the "CanonicalUrl" field is the url of the original post. The
+// "Syndication" field contains the urls of copies of the post on other sites.
+// If the "UpdatedAt" field isn't set, the time of the last commit of the post
+// is used. Posts with "Unlisted" set to true are only reachable via their url
+// and are not shown in any list or feed.
+//
+// The "SeriesOrder" is the position of the post in its series, starting with
+// 1. The "Related" field contains the ids of posts, which are always shown as
+// related posts. The table of contents is shown unless the "TOC" field is set
+// to false. If "Revisions" is true, the commits of the post are shown.
+type BlogPostFrontMatter struct {
+	Title        string   `yaml:"Title"`
+	Slug         string   `yaml:"Slug"`
+	Permalink    string   `yaml:"Permalink"`
+	Aliases      []string `yaml:"Aliases"`
+	CanonicalUrl string   `yaml:"CanonicalUrl"`
+	Syndication  []string `yaml:"Syndication"`
+	Description  string   `yaml:"Description"`
+	Author       string   `yaml:"Author"`
+	Authors      []string `yaml:"Authors"`
+	AuthorName   string   `yaml:"AuthorName"`
+	AuthorTitle  string   `yaml:"AuthorTitle"`
+	AuthorImage  string   `yaml:"AuthorImage"`
+	PublishedAt  string   `yaml:"PublishedAt"`
+	UpdatedAt    string   `yaml:"UpdatedAt"`
+	Tags         []string `yaml:"Tags"`
+	Image        string   `yaml:"Image"`
+	Draft        bool     `yaml:"Draft"`
+	Unlisted     bool     `yaml:"Unlisted"`
+	Series       string   `yaml:"Series"`
+	SeriesOrder  int      `yaml:"SeriesOrder"`
+	Related      []string `yaml:"Related"`
+	TOC          *bool    `yaml:"TOC"`
+	Revisions    bool     `yaml:"Revisions"`
+}
+
+// splitFrontMatter splits the content of a markdown file into the front matter
+// and the markdown body. The front matter must be at the beginning of the file
+// and must be surrounded by "---" lines.
+func splitFrontMatter(content []byte) ([]byte, []byte, error) {
+	content = bytes.ReplaceAll(content, []byte("\r\n"), []byte("\n"))
+
+	if !bytes.HasPrefix(content, []byte("---\n")) {
+		return nil, nil, errors.New("missing front matter")
+	}
+
+	rest := content[len("---\n"):]
+	if bytes.HasPrefix(rest, []byte("---\n")) {
+		return nil, rest[len("---\n"):], nil
+	}
+
+	end := bytes.Index(rest, []byte("\n---\n"))
+	if end == -1 {
+		if !bytes.HasSuffix(rest, []byte("\n---")) {
+			return nil, nil, errors.New("front matter is not closed")
+		}
+		return rest[:len(rest)-len("\n---")], nil, nil
+	}
+
+	return rest[:end+1], rest[end+len("\n---\n"):], nil
+}
+
+// decodeFrontMatter decodes and validates the front matter of a blog post and
+// returns the parsed "PublishedAt" and "UpdatedAt" fields. The "UpdatedAt"
+// field is the zero time, if it isn't set. It returns all problems of the
+// front matter at once, so that they can be fixed together.
+func decodeFrontMatter(data []byte, loc *time.Location) (BlogPostFrontMatter, time.Time, time.Time, []error) {
+	var frontMatter BlogPostFrontMatter
+	if err := yaml.UnmarshalWithOptions(data, &frontMatter, yaml.DisallowUnknownField()); err != nil {
+		return frontMatter, time.Time{}, time.Time{}, []error{errors.New(yaml.FormatError(err, false, false))}
+	}
+
+	var errs []error
+	for _, field := range []struct {
+		name  string
+		value string
+	}{
+		{name: "Title", value: frontMatter.Title},
+		{name: "Description", value: frontMatter.Description},
+		{name: "PublishedAt", value: frontMatter.PublishedAt},
+	} {
+		if field.value == "" {
+			errs = append(errs, fmt.Errorf("missing %s", field.name))
+		}
+	}
+
+	if frontMatter.Slug != "" && slugify(frontMatter.Slug) != frontMatter.Slug {
+		errs = append(errs, fmt.Errorf("invalid Slug %q: must be a slug, e.g. %q", frontMatter.Slug, slugify(frontMatter.Slug)))
+	}
+	if frontMatter.Slug != "" && frontMatter.Permalink != "" {
+		errs = append(errs, errors.New("cannot use both Slug and Permalink"))
+	}
+	if frontMatter.Permalink != "" {
+		if err := validateUrlPath(frontMatter.Permalink); err != nil {
+			errs = append(errs, fmt.Errorf("invalid Permalink: %w", err))
+		}
+	}
+	for _, alias := range frontMatter.Aliases {
+		if err := validateUrlPath(alias); err != nil {
+			errs = append(errs, fmt.Errorf("invalid alias: %w", err))
+		}
+	}
+
+	if frontMatter.CanonicalUrl != "" {
+		if err := validateAbsoluteUrl(frontMatter.CanonicalUrl); err != nil {
+			errs = append(errs, fmt.Errorf("invalid CanonicalUrl: %w", err))
+		}
+	}
+	for _, syndication := range frontMatter.Syndication {
+		if err := validateAbsoluteUrl(syndication); err != nil {
+			errs = append(errs, fmt.Errorf("invalid Syndication: %w", err))
+		}
+	}
+
+	if frontMatter.Author != "" && len(frontMatter.Authors) > 0 {
+		errs = append(errs, errors.New("cannot use both Author and Authors"))
+	}
+	if frontMatter.Author == "" && len(frontMatter.Authors) == 0 {
+		for _, field := range []struct {
+			name  string
+			value string
+		}{
+			{name: "AuthorName", value: frontMatter.AuthorName},
+			{name: "AuthorTitle", value: frontMatter.AuthorTitle},
+			{name: "AuthorImage", value: frontMatter.AuthorImage},
+		} {
+			if field.value == "" {
+				errs = append(errs, fmt.Errorf("missing %s: must be set, when Author and Authors are not set", field.name))
+			}
+		}
+	}
+	for i, author := range frontMatter.Authors {
+		if author == "" {
+			errs = append(errs, fmt.Errorf("empty author at index %d", i))
+		}
+	}
+
+	if len(frontMatter.Tags) == 0 {
+		errs = append(errs, errors.New("missing Tags"))
+	}
+	for i, tag := range frontMatter.Tags {
+		if tag == "" {
+			errs = append(errs, fmt.Errorf("empty tag at index %d", i))
+		}
+	}
+
+	if frontMatter.Series != "" && frontMatter.SeriesOrder < 1 {
+		errs = append(errs, errors.New("missing SeriesOrder: must be 1 or greater for a post in a series"))
+	}
+	if frontMatter.Series == "" && frontMatter.SeriesOrder != 0 {
+		errs = append(errs, errors.New("SeriesOrder is set without Series"))
+	}
+	if frontMatter.Series != "" && slugify(frontMatter.Series) == "" {
+		errs = append(errs, fmt.Errorf("invalid Series %q: must contain at least one letter or digit", frontMatter.Series))
+	}
+
+	var publishedAt time.Time
+	if frontMatter.PublishedAt != "" {
+		var err error
+		publishedAt, err = parseDate("PublishedAt", frontMatter.PublishedAt, loc)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	var updatedAt time.Time
+	if frontMatter.UpdatedAt != "" {
+		var err error
+		updatedAt, err = parseDate("UpdatedAt", frontMatter.UpdatedAt, loc)
+		if err != nil {
+			errs = append(errs, err)
+		} else if !publishedAt.IsZero() && updatedAt.Before(publishedAt) {
+			errs = append(errs, fmt.Errorf("invalid UpdatedAt %q: must not be before PublishedAt", frontMatter.UpdatedAt))
+		}
+	}
+
+	return frontMatter, publishedAt, updatedAt, errs
+}
+
+// parseDate parses a date field of a blog post, e.g. "PublishedAt". The value
+// can be in RFC 3339 format or in the "2006-01-02 15:04:05" format, which is
+// parsed in the given location.
+func parseDate(field, value string, loc *time.Location) (time.Time, error) {
+	if date, err := time.Parse(time.RFC3339, value); err == nil {
+		return date, nil
+	}
+
+	date, err := time.ParseInLocation(publishedAtLayout, value, loc)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid %s %q: must be in RFC 3339 or %q format", field, value, publishedAtLayout)
+	}
+
+	return date, nil
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSplitFrontMatter(t *testing.T) {
+	for _, tt := range []struct {
+		name        string
+		content     string
+		frontMatter string
+		body        string
+		err         string
+	}{
+		{name: "front matter and body", content: "---\nTitle: Test\n---\n\nBody\n", frontMatter: "Title: Test\n", body: "\nBody\n"},
+		{name: "windows line endings", content: "---\r\nTitle: Test\r\n---\r\nBody\r\n", frontMatter: "Title: Test\n", body: "Body\n"},
+		{name: "empty front matter", content: "---\n---\nBody\n", frontMatter: "", body: "Body\n"},
+		{name: "without body", content: "---\nTitle: Test\n---", frontMatter: "Title: Test", body: ""},
+		{name: "separator in body", content: "---\nTitle: Test\n---\nBody\n---\nMore\n", frontMatter: "Title: Test\n", body: "Body\n---\nMore\n"},
+		{name: "missing front matter", content: "Body\n", err: "missing front matter"},
+		{name: "front matter not at the beginning", content: "\n---\nTitle: Test\n---\n", err: "missing front matter"},
+		{name: "front matter not closed", content: "---\nTitle: Test\nBody\n", err: "front matter is not closed"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			frontMatter, body, err := splitFrontMatter([]byte(tt.content))
+			if tt.err != "" {
+				if err == nil || err.Error() != tt.err {
+					t.Fatalf("expected error %q, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(frontMatter) != tt.frontMatter {
+				t.Errorf("expected front matter %q, got %q", tt.frontMatter, frontMatter)
+			}
+			if string(body) != tt.body {
+				t.Errorf("expected body %q, got %q", tt.body, body)
+			}
+		})
+	}
+}
+
+func TestDecodeFrontMatter(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const valid = `Title: Test
+Description: A test post.
+AuthorName: Jane Doe
+AuthorTitle: Engineer
+AuthorImage: /jane.webp
+Tags:
+  - go
+`
+
+	for _, tt := range []struct {
+		name        string
+		frontMatter string
+		publishedAt time.Time
+		updatedAt   time.Time
+		errs        []string
+	}{
+		{
+			name:        "old layout in site timezone",
+			frontMatter: valid + "PublishedAt: 2025-06-28 12:00:00\n",
+			publishedAt: time.Date(2025, 6, 28, 12, 0, 0, 0, loc),
+		},
+		{
+			name:        "RFC 3339 with own offset",
+			frontMatter: valid + "PublishedAt: 2025-06-28T12:00:00Z\nUpdatedAt: 2025-07-01T08:30:00+02:00\n",
+			publishedAt: time.Date(2025, 6, 28, 12, 0, 0, 0, time.UTC),
+			updatedAt:   time.Date(2025, 7, 1, 6, 30, 0, 0, time.UTC),
+		},
+		{
+			name:        "invalid date",
+			frontMatter: valid + "PublishedAt: 28.06.2025\n",
+			errs:        []string{`invalid PublishedAt "28.06.2025"`},
+		},
+		{
+			name:        "updated before published",
+			frontMatter: valid + "PublishedAt: 2025-06-28 12:00:00\nUpdatedAt: 2025-06-27 12:00:00\n",
+			publishedAt: time.Date(2025, 6, 28, 12, 0, 0, 0, loc),
+			updatedAt:   time.Date(2025, 6, 27, 12, 0, 0, 0, loc),
+			errs:        []string{"must not be before PublishedAt"},
+		},
+		{
+			name:        "collects all errors",
+			frontMatter: "AuthorName: Jane Doe\nSeriesOrder: 2\n",
+			errs: []string{
+				"missing Title",
+				"missing Description",
+				"missing PublishedAt",
+				"missing AuthorTitle",
+				"missing AuthorImage",
+				"missing Tags",
+				"SeriesOrder is set without Series",
+			},
+		},
+		{
+			name:        "unknown field",
+			frontMatter: valid + "PublishedAt: 2025-06-28 12:00:00\nTitel: Typo\n",
+			errs:        []string{`unknown field "Titel"`},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			_, publishedAt, updatedAt, errs := decodeFrontMatter([]byte(tt.frontMatter), loc)
+
+			if len(errs) != len(tt.errs) {
+				t.Fatalf("expected %d errors, got %v", len(tt.errs), errs)
+			}
+			for i, err := range errs {
+				if !strings.Contains(err.Error(), tt.errs[i]) {
+					t.Errorf("expected error %d to contain %q, got %q", i, tt.errs[i], err)
+				}
+			}
+
+			if !publishedAt.Equal(tt.publishedAt) {
+				t.Errorf("expected PublishedAt %s, got %s", tt.publishedAt, publishedAt)
+			}
+			if !updatedAt.Equal(tt.updatedAt) {
+				t.Errorf("expected UpdatedAt %s, got %s", tt.updatedAt, updatedAt)
+			}
+		})
+	}
+}
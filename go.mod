@@ -6,11 +6,9 @@ require (
 	github.com/PuerkitoBio/goquery v1.12.0
 	github.com/goccy/go-yaml v1.19.2
 	github.com/yuin/goldmark v1.8.2
-	github.com/yuin/goldmark-meta v1.1.0
 )
 
 require (
 	github.com/andybalholm/cascadia v1.3.3 // indirect
 	golang.org/x/net v0.52.0 // indirect
-	gopkg.in/yaml.v2 v2.3.0 // indirect
 )
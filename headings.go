@@ -0,0 +1,155 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// headingAnchorClass is the class of the anchor links, which are added to the
+// headings by the HeadingRenderer.
+const headingAnchorClass = "heading-anchor"
+
+// headingIDs generates the ids of the headings in a markdown document. The id
+// of a heading is the slug of its text, so that it only changes when the text
+// of the heading is changed. If the slug is already used, a number is appended
+// to it, e.g. "installation-1" for the second "Installation" heading.
+//
+// The generated ids are always unique, but an explicit id, e.g. "## Heading
+// {#id}", can be the same as the id of another heading. These ids are
+// collected in "duplicates".
+type headingIDs struct {
+	values     map[string]bool
+	duplicates []string
+}
+
+func newHeadingIDs() *headingIDs {
+	return &headingIDs{
+		values: make(map[string]bool),
+	}
+}
+
+func (s *headingIDs) Generate(value []byte, kind ast.NodeKind) []byte {
+	id := slugify(string(value))
+	if id == "" {
+		id = "heading"
+	}
+
+	result := id
+	for i := 1; s.values[result]; i++ {
+		result = fmt.Sprintf("%s-%d", id, i)
+	}
+
+	s.values[result] = true
+	return []byte(result)
+}
+
+func (s *headingIDs) Put(value []byte) {
+	if s.values[string(value)] && !slices.Contains(s.duplicates, string(value)) {
+		s.duplicates = append(s.duplicates, string(value))
+	}
+	s.values[string(value)] = true
+}
+
+// renderMarkdown parses and renders the markdown source with the given heading
+// ids. All documents, which are rendered into the same page, must use the same
+// heading ids, so that the ids are unique within the page. The parsed document
+// is returned, so that it can be used for further processing, e.g. for the
+// table of contents.
+func renderMarkdown(md goldmark.Markdown, ids *headingIDs, source []byte, w io.Writer) (ast.Node, error) {
+	ctx := parser.NewContext(parser.WithIDs(ids))
+	doc := md.Parser().Parse(text.NewReader(source), parser.WithContext(ctx))
+	return doc, md.Renderer().Render(w, source, doc)
+}
+
+// HeadingExtender enables the ids of the headings, including explicit ids via
+// attributes like "## Heading {#id}", and renders an anchor link for every
+// heading, so that every section can be linked.
+type HeadingExtender struct{}
+
+func NewHeadingExtender() goldmark.Extender {
+	return &HeadingExtender{}
+}
+
+func (e *HeadingExtender) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(
+		parser.WithAutoHeadingID(),
+		parser.WithAttribute(),
+	)
+	m.Renderer().AddOptions(
+		renderer.WithNodeRenderers(
+			util.Prioritized(NewHeadingRenderer(), 500),
+		),
+	)
+}
+
+type HeadingRenderer struct {
+	html.Config
+}
+
+func NewHeadingRenderer() renderer.NodeRenderer {
+	return &HeadingRenderer{}
+}
+
+func (r *HeadingRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(ast.KindHeading, r.render)
+}
+
+func (r *HeadingRenderer) render(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	n := node.(*ast.Heading)
+
+	if entering {
+		_, _ = w.WriteString("<h")
+		_ = w.WriteByte("0123456"[n.Level])
+		if n.Attributes() != nil {
+			html.RenderAttributes(w, node, html.HeadingAttributeFilter)
+		}
+		_ = w.WriteByte('>')
+
+		return ast.WalkContinue, nil
+	}
+
+	if value, ok := n.AttributeString("id"); ok {
+		if id, ok := value.([]byte); ok {
+			_, _ = w.WriteString(`<a class="` + headingAnchorClass + `" href="#`)
+			_, _ = w.Write(util.EscapeHTML(util.URLEscape(id, false)))
+			_, _ = w.WriteString(`" aria-label="Link to this section">#</a>`)
+		}
+	}
+
+	_, _ = w.WriteString("</h")
+	_ = w.WriteByte("0123456"[n.Level])
+	_, _ = w.WriteString(">\n")
+
+	return ast.WalkContinue, nil
+}
+
+// removeHeadingLinks removes the anchor links and the ids of the headings from
+// the html, e.g. for the excerpt of a post, which is shown on other pages
+// together with the excerpts of other posts, where the links would not work and
+// the ids would not be unique.
+func removeHeadingLinks(content string) (string, error) {
+	if !strings.Contains(content, "<h") {
+		return content, nil
+	}
+
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(content))
+	if err != nil {
+		return "", err
+	}
+
+	doc.Find(fmt.Sprintf("a.%s", headingAnchorClass)).Remove()
+	doc.Find("h1, h2, h3, h4, h5, h6").RemoveAttr("id")
+
+	return doc.Find("body").Html()
+}
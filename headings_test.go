@@ -0,0 +1,41 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestFormatMarkdownSharesHeadingIDs(t *testing.T) {
+	ids := newHeadingIDs()
+	format := formatMarkdown(ids)
+
+	first := string(format("## Usage"))
+	second := string(format("## Usage"))
+	third := string(format("## Other {#usage}"))
+
+	if !strings.Contains(first, `<h2 id="usage">`) {
+		t.Errorf("unexpected first item: %s", first)
+	}
+	if !strings.Contains(second, `<h2 id="usage-1">`) {
+		t.Errorf("expected a unique id for the second item: %s", second)
+	}
+	if !strings.Contains(third, `<h2 id="usage">`) {
+		t.Errorf("expected the explicit id for the third item: %s", third)
+	}
+	if !slices.Equal(ids.duplicates, []string{"usage"}) {
+		t.Errorf("expected the duplicate id %q, got %v", "usage", ids.duplicates)
+	}
+}
+
+func TestRemoveHeadingLinks(t *testing.T) {
+	content := string(formatMarkdown(newHeadingIDs())("## Introduction\n\nSome *text*.\n"))
+
+	result, err := removeHeadingLinks(content)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "<h2>Introduction</h2>\n<p>Some <em>text</em>.</p>\n"; result != expected {
+		t.Errorf("expected %q, got %q", expected, result)
+	}
+}
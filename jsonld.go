@@ -0,0 +1,60 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"log/slog"
+	"time"
+)
+
+// blogPostJsonLd returns the structured data of a blog post, which is added to
+// the head of the post page, so that search engines know when the post was
+// published and last updated.
+//
+// See https://schema.org/BlogPosting
+func blogPostJsonLd(site Site, post BlogPost) template.JS {
+	var sameAs []string
+	for _, syndication := range post.Syndication {
+		sameAs = append(sameAs, syndication.Url)
+	}
+
+	var authors []map[string]any
+	for _, author := range post.Authors {
+		person := map[string]any{
+			"@type":    "Person",
+			"name":     author.Name,
+			"jobTitle": author.Title,
+		}
+		if author.Url != "" {
+			person["url"] = fmt.Sprintf("%s%s", site.BaseUrl, author.Url)
+		}
+		authors = append(authors, person)
+	}
+
+	ld := map[string]any{
+		"@context":         "https://schema.org",
+		"@type":            "BlogPosting",
+		"headline":         post.Title,
+		"description":      post.Description,
+		"image":            fmt.Sprintf("%s%s", site.BaseUrl, post.Image),
+		"url":              post.CanonicalUrl,
+		"mainEntityOfPage": post.CanonicalUrl,
+		"datePublished":    post.PublishedAt.Format(time.RFC3339),
+		"dateModified":     post.UpdatedAt.Format(time.RFC3339),
+		"keywords":         tagNames(post.Tags),
+		"author":           authors,
+	}
+	if len(sameAs) > 0 {
+		ld["sameAs"] = sameAs
+	}
+
+	data, err := json.Marshal(ld)
+	if err != nil {
+		slog.Error("Failed to create structured data", slog.String("post", post.ID), slog.Any("error", err))
+		return ""
+	}
+
+	// #nosec G203
+	return template.JS(data)
+}
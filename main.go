@@ -9,31 +9,30 @@ import (
 	"html/template"
 	"io/fs"
 	"log/slog"
+	"maps"
 	"mime"
-	"net/http"
 	"net/url"
 	"os"
+	"path"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/PuerkitoBio/goquery"
 	"github.com/goccy/go-yaml"
 	"github.com/yuin/goldmark"
-	meta "github.com/yuin/goldmark-meta"
 	"github.com/yuin/goldmark/ast"
 	"github.com/yuin/goldmark/extension"
-	"github.com/yuin/goldmark/parser"
 	"github.com/yuin/goldmark/renderer"
 	"github.com/yuin/goldmark/renderer/html"
 	"github.com/yuin/goldmark/util"
 )
 
-const defaultBaseUrl = "https://ricoberger.de"
-const defaultImage = "/assets/img/social-preview.png"
-
 type Data struct {
+	Site     Site
 	Metadata Metadata
 	Content  any
 }
@@ -47,6 +46,16 @@ type Metadata struct {
 	Url         string
 	Image       string
 	Prism       bool
+	PrevUrl     string
+	NextUrl     string
+	JsonLd      template.JS
+	// CanonicalUrl is the absolute url of the original page. If it is empty,
+	// the url of the page on this site is used, unless the page isn't indexed.
+	CanonicalUrl string
+	// NoIndex prevents search engines from indexing the page. A page, which
+	// isn't indexed, doesn't get a canonical link to itself, e.g. the 404 page,
+	// which is returned for every missing url.
+	NoIndex bool
 }
 
 type CheatSheet struct {
@@ -76,27 +85,61 @@ type CheatSheetTip struct {
 }
 
 type BlogPost struct {
-	ID          string
-	Title       string
-	Description string
-	AuthorName  string
-	AuthorTitle string
-	AuthorImage string
-	PublishedAt time.Time
-	Tags        []string
-	Image       string
-	Content     template.HTML
+	ID            string
+	Url           string
+	Aliases       []string
+	CanonicalUrl  string
+	Syndication   []BlogPostSyndication
+	Title         string
+	Description   string
+	AuthorName    string
+	AuthorTitle   string
+	AuthorImage   string
+	Authors       []BlogPostAuthor
+	PublishedAt   time.Time
+	UpdatedAt     time.Time
+	Revisions     []BlogPostRevision
+	Draft         bool
+	Scheduled     bool
+	Unlisted      bool
+	Tags          []BlogPostTag
+	Image         string
+	SeriesName    string
+	SeriesOrder   int
+	Series        *BlogSeries
+	RelatedIDs    []string
+	Related       []BlogPostLink
+	Prev          *BlogPostLink
+	Next          *BlogPostLink
+	TagNavigation []BlogPostTagNavigation
+	WordCount     int
+	ReadingTime   int
+	TOC           []TocEntry
+	Excerpt       template.HTML
+	Content       template.HTML
+}
+
+// Updated returns true, when the post was updated on a later day than it was
+// published.
+func (p BlogPost) Updated() bool {
+	return p.UpdatedAt.Format(time.DateOnly) > p.PublishedAt.Format(time.DateOnly)
 }
 
 type BlogTag struct {
-	Tag   string
-	Posts []BlogPost
+	Tag         string
+	Slug        string
+	Description string
+	Image       string
+	Posts       []BlogPost
+	Pager       Pager
 }
 
 type RssFeedXml struct {
 	XMLName          xml.Name `xml:"rss"`
 	Version          string   `xml:"version,attr"`
 	ContentNamespace string   `xml:"xmlns:content,attr"`
+	AtomNamespace    string   `xml:"xmlns:atom,attr"`
+	DcNamespace      string   `xml:"xmlns:dc,attr"`
 	Channel          *RssFeed
 }
 
@@ -132,10 +175,19 @@ type RssItem struct {
 	Title       string   `xml:"title"`
 	Link        string   `xml:"link"`
 	Description *RssDescription
-	Author      string `xml:"author,omitempty"`
+	Author      string   `xml:"author,omitempty"`
+	Creators    []string `xml:"dc:creator"`
 	Enclosure   *RssEnclosure
 	Guid        *RssGuid
 	PubDate     string `xml:"pubDate,omitempty"`
+	Updated     string `xml:"atom:updated,omitempty"`
+	AtomLinks   []*AtomLink
+}
+
+type AtomLink struct {
+	XMLName xml.Name `xml:"atom:link"`
+	Rel     string   `xml:"rel,attr"`
+	Href    string   `xml:"href,attr"`
 }
 
 type RssEnclosure struct {
@@ -164,64 +216,185 @@ type SitemapItem struct {
 }
 
 func main() {
-	var serve bool
-
-	flag.BoolVar(&serve, "serve", false, "Start a local server to preview the generated site.")
-	flag.Parse()
-
-	if serve {
-		fs := http.FileServer(http.Dir("./dist"))
-		http.Handle("/", fs)
-
-		slog.Info("Start server on :9999...")
-		server := &http.Server{
-			Addr:         ":9999",
-			ReadTimeout:  10 * time.Second,
-			WriteTimeout: 10 * time.Second,
-		}
-		if err := server.ListenAndServe(); err != nil {
-			slog.Error("Failed to start server", slog.Any("error", err))
-			os.Exit(1)
-		}
-	} else {
-		if err := build(); err != nil {
-			slog.Error("Failed to build site", slog.Any("error", err))
-			os.Exit(1)
+	if err := run(os.Args[1:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return
 		}
+
+		fmt.Fprintf(os.Stderr, "\n%s\n", err.Error())
+		os.Exit(1)
 	}
 }
 
-func build() error {
-	slog.Info("Start build...")
+// BuildOptions are the options for a build, which can be set via the
+// command-line flags. The "Config" file is relative to the "Src" directory.
+type BuildOptions struct {
+	Src       string
+	Out       string
+	Config    string
+	KeepGoing bool
+	Jobs      int
+	CacheDir  string
+	Force     bool
+	Drafts    bool
+	Future    bool
+}
 
-	slog.Info("Build home...")
-	err := buildHome()
-	if err != nil {
-		slog.Error("Failed to build home", slog.Any("error", err))
+// newBuilder loads the site configuration and returns a builder, which reads
+// from the "Src" directory and writes into the "Out" directory. The returned
+// builder doesn't use the build cache.
+func newBuilder(opts BuildOptions) (*Builder, error) {
+	config := opts.Config
+	if !filepath.IsAbs(config) {
+		config = filepath.Join(opts.Src, config)
 	}
 
-	slog.Info("Build page not found...")
-	err = buildPageNotFound()
+	site, err := loadSite(os.DirFS(filepath.Dir(config)), filepath.Base(config))
 	if err != nil {
-		slog.Error("Failed to build page not found", slog.Any("error", err))
+		return nil, fmt.Errorf("failed to load site configuration: %w", err)
 	}
 
-	slog.Info("Build cheat sheets...")
-	err = buildCheatSheets()
+	buildDate, err := buildTime()
 	if err != nil {
-		slog.Error("Failed to build cheat sheets", slog.Any("error", err))
+		return nil, err
 	}
 
-	slog.Info("Build blog...")
-	err = buildBlog()
+	builder := newSiteBuilder(site, os.DirFS(opts.Src), newDirOutput(opts.Out), opts)
+	builder.buildDate = buildDate
+	builder.gitDir = opts.Src
+
+	return builder, nil
+}
+
+// newSiteBuilder returns a builder for the site, which reads all sources from
+// the "src" file system and writes the site into "out". The "Src", "Out" and
+// "Config" options are ignored, so that a site can also be built from an
+// fstest.MapFS into a MemOutput.
+func newSiteBuilder(site Site, src fs.FS, out Output, opts BuildOptions) *Builder {
+	return &Builder{
+		site:      site,
+		src:       src,
+		out:       out,
+		keepGoing: opts.KeepGoing,
+		jobs:      opts.Jobs,
+		drafts:    opts.Drafts,
+		future:    opts.Future,
+		now:       time.Now(),
+		buildDate: time.Now(),
+	}
+}
+
+// runBuild loads the site configuration and the build cache and builds the
+// site with the given options.
+func runBuild(opts BuildOptions) error {
+	builder, err := newBuilder(opts)
 	if err != nil {
-		slog.Error("Failed to build blog", slog.Any("error", err))
+		return err
 	}
 
-	slog.Info("Build sitemap...")
-	err = buildSitemap()
+	builder.cache, err = loadCache(opts.CacheDir, opts.Out, builder.out, opts.Force)
 	if err != nil {
-		slog.Error("Failed to build sitemap", slog.Any("error", err))
+		return fmt.Errorf("failed to load build cache: %w", err)
+	}
+
+	return builder.build()
+}
+
+// Builder builds the site. It holds the site configuration and collects the
+// errors of all phases, so that they can be reported at the end of the build.
+//
+// The pages of a phase are built concurrently with the configured number of
+// jobs. Each page is written to its own file, so that the output is the same as
+// for a sequential build. If a cache is set, pages whose inputs didn't change
+// since the last build are skipped.
+//
+// Draft posts and posts with a publish date after "now" are only built, when
+// the "drafts" or "future" option is set.
+//
+// All sources are read from the "src" file system and all files are written to
+// the "out" output, so that the paths of the sources and outputs are always
+// relative to these roots.
+//
+// The dates in the feeds and the sitemap are derived from the content, i.e.
+// the publish dates of the posts and the commit times of the sources in the
+// "gitDir" repository, so that they only change when the content changes. The
+// "buildDate" is only used, when a date can not be derived from the content.
+type Builder struct {
+	site      Site
+	src       fs.FS
+	out       Output
+	keepGoing bool
+	jobs      int
+	cache     *Cache
+	drafts    bool
+	future    bool
+	now       time.Time
+	buildDate time.Time
+	gitDir    string
+	errs      BuildErrors
+
+	posts       []BlogPost
+	tags        map[string][]BlogPost
+	series      []*BlogSeries
+	cheatSheets []CheatSheet
+
+	tagConfigs map[string]TagConfig
+	tagAliases map[string]string
+	tagNames   map[string]string
+
+	authorConfigs map[string]AuthorConfig
+
+	templatesMu sync.Mutex
+	templates   map[string]*parsedTemplate
+
+	modTimesOnce sync.Once
+	modTimes     map[string]time.Time
+}
+
+// parsedTemplate is a parsed template together with the hash of its source
+// files, which is used as input for the build cache.
+type parsedTemplate struct {
+	template *template.Template
+	hash     string
+}
+
+// build runs all phases of the build. When a phase fails, the remaining phases
+// are skipped, unless the "keepGoing" option is set. The returned error
+// contains all errors which occurred during the build.
+func (b *Builder) build() error {
+	slog.Info("Start build...")
+
+	phases := []struct {
+		name  string
+		build func()
+	}{
+		{name: "home", build: b.buildHome},
+		{name: "page not found", build: b.buildPageNotFound},
+		{name: "cheat sheets", build: b.buildCheatSheets},
+		{name: "blog", build: b.buildBlog},
+		{name: "sitemap", build: b.buildSitemap},
+	}
+
+	for _, phase := range phases {
+		slog.Info(fmt.Sprintf("Build %s...", phase.name))
+
+		errs := b.errs.Len()
+		phase.build()
+
+		if failed := b.errs.Len() - errs; failed > 0 {
+			slog.Error(fmt.Sprintf("Failed to build %s", phase.name), slog.Int("errors", failed))
+			if !b.keepGoing {
+				break
+			}
+		}
+	}
+
+	if err := b.cache.Save(b.errs.Len() == 0); err != nil {
+		slog.Error("Failed to save build cache", slog.Any("error", err))
+	}
+
+	if err := b.errs.Err(); err != nil {
+		return err
 	}
 
 	slog.Info("Build done")
@@ -241,156 +414,217 @@ func exists(path string) (bool, error) {
 	return false, err
 }
 
-func buildTemplate(tmpl string, distPath string, data Data) error {
-	if err := os.MkdirAll(distPath, os.ModePerm); err != nil {
+// copyAssets copies all files of the "src" directory to the "dst" directory of
+// the output, when the "src" directory exists. Every file is built via the
+// cache, so that only changed files are copied and only files, which were
+// removed from the "src" directory, are removed from the output. Other files
+// in the "dst" directory, e.g. the generated CSS file, are kept.
+func (b *Builder) copyAssets(dst, src string) error {
+	if _, err := fs.Stat(b.src, src); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
 		return err
 	}
 
-	templates, err := template.New("base.html").Funcs(template.FuncMap{
-		"formatMarkdown": func(s string) template.HTML {
-			md := goldmark.New(
-				goldmark.WithExtensions(
-					extension.Table,
-					extension.Strikethrough,
-					NewImageExtender(),
-				),
-				goldmark.WithRendererOptions(
-					html.WithUnsafe(),
-				),
-			)
-
-			var buf bytes.Buffer
-			if err := md.Convert([]byte(s), &buf); err != nil {
-				slog.Error("Failed to convert markdown", slog.Any("error", err))
+	return fs.WalkDir(b.src, src, func(name string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		hash, err := hashFiles(b.src, name)
+		if err != nil {
+			return err
+		}
+
+		file := path.Join(dst, strings.TrimPrefix(name, src+"/"))
+
+		return b.cache.Build(file, hash, func() error {
+			data, err := fs.ReadFile(b.src, name)
+			if err != nil {
+				return err
 			}
-			// #nosec G203
-			return template.HTML(buf.String())
-		},
-	}).ParseFiles("templates/base.html", fmt.Sprintf("templates/%s.html", tmpl))
+
+			return b.out.WriteFile(file, data)
+		})
+	})
+}
+
+// parseTemplate parses the given template together with the "base.html"
+// template. The parsed templates are cached, so that every template is only
+// parsed once per build, even when it is used for multiple pages.
+func (b *Builder) parseTemplate(tmpl string) (*parsedTemplate, error) {
+	b.templatesMu.Lock()
+	defer b.templatesMu.Unlock()
+
+	if parsed, ok := b.templates[tmpl]; ok {
+		return parsed, nil
+	}
+
+	files := []string{"templates/base.html", fmt.Sprintf("templates/%s.html", tmpl)}
+
+	hash, err := hashFiles(b.src, files...)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	f, err := os.Create(fmt.Sprintf("%s/index.html", distPath))
+	// The "formatMarkdown" function is replaced for every page, so that all
+	// markdown of a page shares the same heading ids, see renderTemplate.
+	templates, err := template.New("base.html").Funcs(template.FuncMap{
+		"formatMarkdown": formatMarkdown(newHeadingIDs()),
+	}).ParseFS(b.src, files...)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	if err := templates.Execute(f, data); err != nil {
-		return err
+	if b.templates == nil {
+		b.templates = make(map[string]*parsedTemplate)
 	}
+	b.templates[tmpl] = &parsedTemplate{template: templates, hash: hash}
 
-	return nil
+	return b.templates[tmpl], nil
 }
 
-func buildHome() error {
-	var homeData = Data{
-		Metadata: Metadata{
-			Title:       "Home - Rico Berger",
-			Description: "Site Reliability Engineer, Hacker, Cloud Native Enthusiast",
-			Author:      "Rico Berger",
-			Keywords:    []string{"Rico Berger", "Site Reliability Engineer", "Hacker", "Cloud Native Enthusiast"},
-			BaseUrl:     defaultBaseUrl,
-			Url:         "/",
-			Image:       defaultImage,
-			Prism:       false,
-		},
-	}
+// renderTemplate renders the given template with the data into the file. The
+// file is only rendered, when the template or the data changed since the last
+// build.
+func (b *Builder) renderTemplate(tmpl string, file string, data Data) error {
+	data.Site = b.site
 
-	if err := buildTemplate("home", "./dist", homeData); err != nil {
+	parsed, err := b.parseTemplate(tmpl)
+	if err != nil {
 		return err
 	}
 
-	var aboutData = Data{
-		Metadata: Metadata{
-			Title:       "About - Rico Berger",
-			Description: "Site Reliability Engineer, Hacker, Cloud Native Enthusiast",
-			Author:      "Rico Berger",
-			Keywords:    []string{"Rico Berger", "Site Reliability Engineer", "Hacker", "Cloud Native Enthusiast"},
-			BaseUrl:     defaultBaseUrl,
-			Url:         "/about/",
-			Image:       defaultImage,
-			Prism:       false,
-		},
-	}
-
-	if err := buildTemplate("about", "./dist/about", aboutData); err != nil {
+	hash, err := hashOf(parsed.hash, data)
+	if err != nil {
 		return err
 	}
 
-	var analyticsData = Data{
-		Metadata: Metadata{
-			Title:       "Analytics - Rico Berger",
-			Description: "Site Reliability Engineer, Hacker, Cloud Native Enthusiast",
-			Author:      "Rico Berger",
-			Keywords:    []string{"Rico Berger", "Site Reliability Engineer", "Hacker", "Cloud Native Enthusiast"},
-			BaseUrl:     defaultBaseUrl,
-			Url:         "/analytics/",
-			Image:       defaultImage,
-			Prism:       false,
-		},
-	}
+	return b.cache.Build(file, hash, func() error {
+		// The parsed template is never executed, so that it can be cloned for
+		// every page with its own heading ids.
+		ids := newHeadingIDs()
 
-	if err := buildTemplate("analytics", "./dist/analytics", analyticsData); err != nil {
-		return err
-	}
+		page, err := parsed.template.Clone()
+		if err != nil {
+			return err
+		}
+		page.Funcs(template.FuncMap{"formatMarkdown": formatMarkdown(ids)})
 
-	if err := os.CopyFS("./dist/assets", os.DirFS("./templates/assets")); err != nil {
-		return err
-	}
+		var buf bytes.Buffer
+		if err := page.Execute(&buf, data); err != nil {
+			return err
+		}
 
-	return nil
+		for _, id := range ids.duplicates {
+			slog.Warn("Duplicate heading id", slog.String("file", file), slog.String("id", id))
+		}
+
+		return b.out.WriteFile(file, buf.Bytes())
+	})
 }
 
-func buildPageNotFound() error {
-	var pageNotFoundData = Data{
-		Metadata: Metadata{
-			Title:       "404 - Not Found - Rico Berger",
-			Description: "Site Reliability Engineer, Hacker, Cloud Native Enthusiast",
-			Author:      "Rico Berger",
-			Keywords:    []string{"Rico Berger", "Site Reliability Engineer", "Hacker", "Cloud Native Enthusiast"},
-			BaseUrl:     defaultBaseUrl,
-			Url:         "/",
-			Image:       defaultImage,
-			Prism:       false,
-		},
-	}
+// formatMarkdown returns the "formatMarkdown" function for the templates, which
+// renders markdown with the given heading ids.
+func formatMarkdown(ids *headingIDs) func(s string) template.HTML {
+	md := goldmark.New(
+		goldmark.WithExtensions(
+			extension.Table,
+			extension.Strikethrough,
+			NewImageExtender(),
+			NewHeadingExtender(),
+		),
+		goldmark.WithRendererOptions(
+			html.WithUnsafe(),
+		),
+	)
 
-	templates, err := template.New("base.html").ParseFiles("templates/base.html", "templates/404.html")
-	if err != nil {
-		return err
+	return func(s string) template.HTML {
+		var buf bytes.Buffer
+		if _, err := renderMarkdown(md, ids, []byte(s), &buf); err != nil {
+			slog.Error("Failed to convert markdown", slog.Any("error", err))
+		}
+		// #nosec G203
+		return template.HTML(buf.String())
 	}
+}
 
-	f, err := os.Create("./dist/404.html")
-	if err != nil {
-		return err
-	}
+func (b *Builder) buildTemplate(tmpl string, distPath string, data Data) error {
+	return b.renderTemplate(tmpl, path.Join(distPath, "index.html"), data)
+}
 
-	if err := templates.Execute(f, pageNotFoundData); err != nil {
-		return err
+func (b *Builder) buildHome() {
+	site := b.site
+	p := newPool(b.jobs)
+
+	p.Go(func() {
+		var homeData = Data{
+			Metadata: site.metadata(site.Sections.Home, "/"),
+		}
+
+		b.errs.Add("templates/home.html", "render", b.buildTemplate("home", "", homeData))
+	})
+
+	p.Go(func() {
+		var aboutData = Data{
+			Metadata: site.metadata(site.Sections.About, "/about/"),
+		}
+
+		b.errs.Add("templates/about.html", "render", b.buildTemplate("about", "about", aboutData))
+	})
+
+	p.Go(func() {
+		var analyticsData = Data{
+			Metadata: site.metadata(site.Sections.Analytics, "/analytics/"),
+		}
+
+		b.errs.Add("templates/analytics.html", "render", b.buildTemplate("analytics", "analytics", analyticsData))
+	})
+
+	p.Go(func() {
+		b.errs.Add("templates/assets", "assets", b.copyAssets("assets", "templates/assets"))
+	})
+
+	p.Wait()
+}
+
+func (b *Builder) buildPageNotFound() {
+	site := b.site
+
+	var pageNotFoundData = Data{
+		Metadata: site.metadata(site.Sections.NotFound, "/"),
 	}
+	pageNotFoundData.Metadata.NoIndex = true
 
-	return nil
+	b.errs.Add("templates/404.html", "render", b.renderTemplate("404", "404.html", pageNotFoundData))
 }
 
-func buildCheatSheets() error {
-	files, err := os.ReadDir("./cheat-sheets")
+// readCheatSheets reads all cheat sheets from the "cheat-sheets/<id>/<id>.yaml"
+// files. Invalid cheat sheets are added to the build errors and skipped.
+func (b *Builder) readCheatSheets() []CheatSheet {
+	files, err := fs.ReadDir(b.src, "cheat-sheets")
 	if err != nil {
-		return err
+		b.errs.Add("cheat-sheets", "read", err)
+		return nil
 	}
 
 	var cheatSheets []CheatSheet
 
 	for _, file := range files {
 		if file.IsDir() {
-			content, err := os.ReadFile(fmt.Sprintf("./cheat-sheets/%s/%s.yaml", file.Name(), file.Name()))
+			path := fmt.Sprintf("cheat-sheets/%s/%s.yaml", file.Name(), file.Name())
+
+			content, err := fs.ReadFile(b.src, path)
 			if err != nil {
-				return err
+				b.errs.Add(path, "read", err)
+				continue
 			}
 
 			var cheatSheet CheatSheet
 			if err := yaml.Unmarshal(content, &cheatSheet); err != nil {
-				return err
+				b.errs.Add(path, "yaml", err)
+				continue
 			}
 			cheatSheet.ID = file.Name()
 
@@ -398,231 +632,471 @@ func buildCheatSheets() error {
 		}
 	}
 
-	var cheatsheetsData = Data{
-		Metadata: Metadata{
-			Title:       "Cheat Sheets - Rico Berger",
-			Description: "Cheat Sheets about Site Reliability Engineering, Platform Engineering, Cloud Native, Kubernetes and more",
-			Author:      "Rico Berger",
-			Keywords:    []string{"Rico Berger", "Cheat Sheets"},
-			BaseUrl:     defaultBaseUrl,
-			Url:         "/cheat-sheets/",
-			Image:       defaultImage,
-			Prism:       false,
-		},
-		Content: cheatSheets,
+	return cheatSheets
+}
+
+func (b *Builder) buildCheatSheets() {
+	site := b.site
+
+	cheatSheets := b.readCheatSheets()
+	b.cheatSheets = cheatSheets
+
+	p := newPool(b.jobs)
+
+	p.Go(func() {
+		var cheatsheetsData = Data{
+			Metadata: site.metadata(site.Sections.CheatSheets, "/cheat-sheets/"),
+			Content:  cheatSheets,
+		}
+
+		b.errs.Add("templates/cheat-sheets.html", "render", b.buildTemplate("cheat-sheets", "cheat-sheets", cheatsheetsData))
+	})
+
+	for _, cheatSheet := range cheatSheets {
+		p.Go(func() {
+			b.errs.Add(fmt.Sprintf("cheat-sheets/%s/%s.yaml", cheatSheet.ID, cheatSheet.ID), "render", b.buildTemplate("cheat-sheet", fmt.Sprintf("cheat-sheets/%s", cheatSheet.ID), Data{
+				Metadata: Metadata{
+					Title:       site.title(cheatSheet.Title, site.Sections.CheatSheets.ItemTitle),
+					Description: cheatSheet.Description,
+					Author:      cheatSheet.Author,
+					Keywords:    cheatSheet.Keywords,
+					BaseUrl:     site.BaseUrl,
+					Url:         fmt.Sprintf("/cheat-sheets/%s/", cheatSheet.ID),
+					Image:       fmt.Sprintf("/cheat-sheets/%s/assets/%s-cheat-sheet.png", cheatSheet.ID, cheatSheet.ID),
+					Prism:       true,
+				},
+				Content: cheatSheet,
+			}))
+
+			b.errs.Add(fmt.Sprintf("cheat-sheets/%s/assets", cheatSheet.ID), "assets", b.copyAssets(fmt.Sprintf("cheat-sheets/%s/assets", cheatSheet.ID), fmt.Sprintf("cheat-sheets/%s/assets", cheatSheet.ID)))
+		})
 	}
 
-	if err := buildTemplate("cheat-sheets", "./dist/cheat-sheets", cheatsheetsData); err != nil {
-		return err
+	p.Wait()
+}
+
+// readBlogPost reads the blog post with the given id from the
+// "blog/<id>/<id>.md" file. If the blog post is invalid, all errors are added
+// to the build errors and false is returned.
+func (b *Builder) readBlogPost(id string) (BlogPost, bool) {
+	site := b.site
+	path := fmt.Sprintf("blog/%s/%s.md", id, id)
+
+	content, err := fs.ReadFile(b.src, path)
+	if err != nil {
+		b.errs.Add(path, "read", err)
+		return BlogPost{}, false
 	}
 
-	for _, cheatSheet := range cheatSheets {
-		if err := buildTemplate("cheat-sheet", fmt.Sprintf("./dist/cheat-sheets/%s", cheatSheet.ID), Data{
-			Metadata: Metadata{
-				Title:       fmt.Sprintf("%s - Cheat Sheet - Rico Berger", cheatSheet.Title),
-				Description: cheatSheet.Description,
-				Author:      cheatSheet.Author,
-				Keywords:    cheatSheet.Keywords,
-				BaseUrl:     defaultBaseUrl,
-				Url:         fmt.Sprintf("/cheat-sheets/%s/", cheatSheet.ID),
-				Image:       fmt.Sprintf("/cheat-sheets/%s/assets/%s-cheat-sheet.png", cheatSheet.ID, cheatSheet.ID),
-				Prism:       true,
-			},
-			Content: cheatSheet,
-		}); err != nil {
-			return err
-		}
+	frontMatterData, body, err := splitFrontMatter(content)
+	if err != nil {
+		b.errs.Add(path, "front matter", err)
+		return BlogPost{}, false
+	}
 
-		hasAssets, err := exists(fmt.Sprintf("./cheat-sheets/%s/assets", cheatSheet.ID))
-		if err != nil {
-			return err
-		}
-		if hasAssets {
-			if err := os.CopyFS(fmt.Sprintf("./dist/cheat-sheets/%s/assets", cheatSheet.ID), os.DirFS(fmt.Sprintf("./cheat-sheets/%s/assets", cheatSheet.ID))); err != nil {
-				return err
-			}
+	frontMatter, publishedAt, updatedAt, errs := decodeFrontMatter(frontMatterData, site.location)
+	if len(errs) > 0 {
+		for _, err := range errs {
+			b.errs.Add(path, "front matter", err)
 		}
+		return BlogPost{}, false
 	}
 
-	return nil
-}
+	var buf bytes.Buffer
+	markdown := goldmark.New(
+		goldmark.WithExtensions(
+			extension.Table,
+			extension.Strikethrough,
+			extension.Footnote,
+			NewImageExtender(),
+			NewHeadingExtender(),
+		),
+		goldmark.WithRendererOptions(
+			html.WithUnsafe(),
+		),
+	)
 
-func buildBlog() error {
-	files, err := os.ReadDir("./blog")
+	// The parsed document is also used for the word count and the table of
+	// contents.
+	ids := newHeadingIDs()
+	doc, err := renderMarkdown(markdown, ids, body, &buf)
 	if err != nil {
-		return err
+		b.errs.Add(path, "markdown", err)
+		return BlogPost{}, false
 	}
 
-	var posts []BlogPost
+	for _, id := range ids.duplicates {
+		slog.Warn("Duplicate heading id", slog.String("file", path), slog.String("id", id))
+	}
 
-	for _, file := range files {
-		if file.IsDir() {
-			content, err := os.ReadFile(fmt.Sprintf("./blog/%s/%s.md", file.Name(), file.Name()))
-			if err != nil {
-				return err
-			}
+	wordCount := countWords(doc, body)
 
-			var buf bytes.Buffer
-			markdown := goldmark.New(
-				goldmark.WithExtensions(
-					meta.Meta,
-					extension.Table,
-					extension.Strikethrough,
-					extension.Footnote,
-					NewImageExtender(),
-				),
-				goldmark.WithRendererOptions(
-					html.WithUnsafe(),
-				),
-			)
-			context := parser.NewContext()
-
-			if err := markdown.Convert(content, &buf, parser.WithContext(context)); err != nil {
-				return err
-			}
+	var toc []TocEntry
+	if frontMatter.TOC == nil || *frontMatter.TOC {
+		toc = tableOfContents(doc, body, site.TocDepth)
+	}
 
-			metaData := meta.Get(context)
+	tags, err := b.resolveTags(frontMatter.Tags)
+	if err != nil {
+		b.errs.Add(path, "front matter", err)
+		return BlogPost{}, false
+	}
 
-			publishedAt, err := time.Parse("2006-01-02 15:04:05", metaData["PublishedAt"].(string))
-			if err != nil {
-				return err
-			}
+	authors, err := b.resolveAuthors(frontMatter)
+	if err != nil {
+		b.errs.Add(path, "front matter", err)
+		return BlogPost{}, false
+	}
 
-			var tags []string
-			for _, tag := range metaData["Tags"].([]any) {
-				tags = append(tags, tag.(string))
-			}
+	image := site.Sections.Blog.Image
+	if frontMatter.Image != "" {
+		image = frontMatter.Image
+	}
 
-			image := defaultImage
-			if val, ok := metaData["Image"]; ok && val != nil {
-				image = val.(string)
+	// If the post doesn't set the "UpdatedAt" field, the publish date is
+	// used. When enabled in the site configuration, the time of the last
+	// commit, which changed the content of the post, is used instead. Posts
+	// can be committed before they are published, so that the publish date is
+	// used in this case.
+	if updatedAt.IsZero() {
+		updatedAt = publishedAt
+		if site.UpdatedFromGit && b.gitDir != "" {
+			if modTime := gitContentModTime(b.gitDir, fmt.Sprintf("blog/%s", id), path); modTime.After(publishedAt) {
+				updatedAt = modTime.In(site.location)
 			}
+		}
+	}
 
-			post := BlogPost{
-				ID:          file.Name(),
-				Title:       metaData["Title"].(string),
-				Description: metaData["Description"].(string),
-				AuthorName:  metaData["AuthorName"].(string),
-				AuthorTitle: metaData["AuthorTitle"].(string),
-				AuthorImage: metaData["AuthorImage"].(string),
-				PublishedAt: publishedAt,
-				Tags:        tags,
-				Image:       image,
-				// #nosec G203
-				Content: template.HTML(buf.String()),
-			}
+	slug := id
+	if frontMatter.Slug != "" {
+		slug = frontMatter.Slug
+	}
+	postUrl := permalink(site.Permalink, slug, publishedAt.In(site.location))
+	if frontMatter.Permalink != "" {
+		postUrl = frontMatter.Permalink
+	}
+
+	// The canonical url is the url of the post on this site, unless the post
+	// was published on another site first.
+	canonicalUrl := fmt.Sprintf("%s%s", site.BaseUrl, postUrl)
+	if frontMatter.CanonicalUrl != "" {
+		canonicalUrl = frontMatter.CanonicalUrl
+	}
+
+	var syndication []BlogPostSyndication
+	for _, value := range frontMatter.Syndication {
+		syndication = append(syndication, newBlogPostSyndication(value))
+	}
+
+	var revisions []BlogPostRevision
+	if frontMatter.Revisions && b.gitDir != "" {
+		revisions = gitRevisions(b.gitDir, fmt.Sprintf("blog/%s", id))
+	}
+
+	postExcerpt, err := removeHeadingLinks(excerpt(doc, body, buf.String()))
+	if err != nil {
+		b.errs.Add(path, "excerpt", err)
+		return BlogPost{}, false
+	}
+
+	return BlogPost{
+		ID:           id,
+		Url:          postUrl,
+		Aliases:      frontMatter.Aliases,
+		CanonicalUrl: canonicalUrl,
+		Syndication:  syndication,
+		Title:        frontMatter.Title,
+		Description:  frontMatter.Description,
+		Authors:      authors,
+		AuthorName:   authors[0].Name,
+		AuthorTitle:  authors[0].Title,
+		AuthorImage:  authors[0].Image,
+		PublishedAt:  publishedAt,
+		UpdatedAt:    updatedAt,
+		Revisions:    revisions,
+		Draft:        frontMatter.Draft,
+		Unlisted:     frontMatter.Unlisted,
+		Scheduled:    publishedAt.After(b.now),
+		Tags:         tags,
+		Image:        image,
+		SeriesName:   frontMatter.Series,
+		SeriesOrder:  frontMatter.SeriesOrder,
+		RelatedIDs:   frontMatter.Related,
+		WordCount:    wordCount,
+		ReadingTime:  readingTime(wordCount),
+		TOC:          toc,
+		// #nosec G203
+		Excerpt: template.HTML(postExcerpt),
+		// #nosec G203
+		Content: template.HTML(buf.String()),
+	}, true
+}
+
+// readBlogPosts reads all blog posts, including drafts and scheduled posts.
+// The posts are returned in the order of their directories. The tags of the
+// posts are normalized with the configuration from the "blog/tags.yaml" file
+// and the authors are resolved with the "blog/authors.yaml" file.
+func (b *Builder) readBlogPosts() []BlogPost {
+	b.loadTags()
+	b.loadAuthors()
+
+	files, err := fs.ReadDir(b.src, "blog")
+	if err != nil {
+		b.errs.Add("blog", "read", err)
+		return nil
+	}
 
+	// The blog posts are read concurrently into a slice with one entry per
+	// directory, so that the order of the posts doesn't depend on the order in
+	// which the jobs are finished.
+	var ids []string
+	for _, file := range files {
+		if file.IsDir() {
+			ids = append(ids, file.Name())
+		}
+	}
+
+	results := make([]BlogPost, len(ids))
+	valid := make([]bool, len(ids))
+
+	p := newPool(b.jobs)
+	for i, id := range ids {
+		p.Go(func() {
+			results[i], valid[i] = b.readBlogPost(id)
+		})
+	}
+	p.Wait()
+
+	var posts []BlogPost
+	for i, post := range results {
+		if valid[i] {
 			posts = append(posts, post)
 		}
 	}
 
-	sort.Slice(posts, func(i, j int) bool {
+	b.nameTags(posts)
+
+	return posts
+}
+
+// sortBlogPosts sorts the posts by their publish date, starting with the newest
+// post. Posts with the same publish date keep their order.
+func sortBlogPosts(posts []BlogPost) {
+	sort.SliceStable(posts, func(i, j int) bool {
 		return posts[i].PublishedAt.After(posts[j].PublishedAt)
 	})
+}
 
-	var blogData = Data{
-		Metadata: Metadata{
-			Title:       "Blog - Rico Berger",
-			Description: "Personal Blog about Site Reliability Engineering, Platform Engineering, Cloud Native, Kubernetes and more",
-			Author:      "Rico Berger",
-			Keywords:    []string{"Rico Berger", "Blog"},
-			BaseUrl:     defaultBaseUrl,
-			Url:         "/blog/",
-			Image:       defaultImage,
-			Prism:       false,
-		},
-		Content: posts,
-	}
+func (b *Builder) buildBlog() {
+	site := b.site
 
-	if err := buildTemplate("blog", "./dist/blog", blogData); err != nil {
-		return err
+	var posts, unlisted []BlogPost
+	var nextScheduled *BlogPost
+
+	all := b.readBlogPosts()
+
+	for _, post := range all {
+		if post.Scheduled && !post.Draft && (nextScheduled == nil || post.PublishedAt.Before(nextScheduled.PublishedAt)) {
+			nextScheduled = &post
+		}
+
+		if (post.Draft && !b.drafts) || (post.Scheduled && !b.future) {
+			continue
+		}
+
+		// Unlisted posts are only built as pages. They are not added to any
+		// list, feed or the sitemap and no other post links to them.
+		if post.Unlisted {
+			unlisted = append(unlisted, post)
+			continue
+		}
+
+		posts = append(posts, post)
 	}
 
-	if err := buildRssFeed("./dist/blog", blogData.Metadata, posts); err != nil {
-		return err
+	// Log when the next scheduled post becomes due, so that a rebuild of the
+	// site can be scheduled for this time.
+	if nextScheduled != nil {
+		slog.Info("Next scheduled post", slog.String("post", nextScheduled.ID), slog.Time("publishedAt", nextScheduled.PublishedAt))
 	}
 
+	sortBlogPosts(posts)
+
+	b.checkUrls(slices.Concat(posts, unlisted))
+	series := b.collectSeries(posts)
+	b.relatePosts(posts, all)
+	linkPosts(posts)
+
+	b.posts = posts
+	b.series = series
+
 	tags := make(map[string][]BlogPost)
+	b.tags = tags
 
 	for _, post := range posts {
-		if err := buildTemplate("blog-post", fmt.Sprintf("./dist/blog/posts/%s", post.ID), Data{
-			Metadata: Metadata{
-				Title:       fmt.Sprintf("%s - Blog - Rico Berger", post.Title),
-				Description: post.Description,
-				Author:      post.AuthorName,
-				Keywords:    post.Tags,
-				BaseUrl:     defaultBaseUrl,
-				Url:         fmt.Sprintf("/blog/%s/", post.ID),
-				Image:       post.Image,
-				Prism:       true,
-			},
-			Content: post,
-		}); err != nil {
-			return err
+		for _, tag := range post.Tags {
+			tags[tag.Slug] = append(tags[tag.Slug], post)
 		}
+	}
 
-		hasAssets, err := exists(fmt.Sprintf("./blog/%s/assets", post.ID))
-		if err != nil {
-			return err
+	blogMetadata := site.metadata(site.Sections.Blog, "/blog/")
+
+	p := newPool(b.jobs)
+
+	for _, page := range paginate(posts, site.Pagination.Blog, blogMetadata.Url) {
+		metadata := blogMetadata
+		metadata.Title = site.title(pageTitle(page.Pager.Page, site.Sections.Blog.Title)...)
+		metadata.Url = page.Url
+		metadata.PrevUrl = page.Pager.PrevUrl
+		metadata.NextUrl = page.Pager.NextUrl
+
+		p.Go(func() {
+			b.errs.Add("templates/blog.html", fmt.Sprintf("render %s", page.Url), b.buildTemplate("blog", urlPath(page.Url), Data{
+				Metadata: metadata,
+				Content:  page,
+			}))
+		})
+	}
+
+	p.Go(func() {
+		b.errs.Add("blog", "feed /blog/feed.xml", b.buildRssFeed("blog", blogMetadata, posts))
+	})
+
+	for _, post := range slices.Concat(posts, unlisted) {
+		metadata := Metadata{
+			Title:        site.title(post.Title, site.Sections.Blog.ItemTitle),
+			Description:  post.Description,
+			Author:       strings.Join(authorNames(post.Authors), ", "),
+			Keywords:     tagNames(post.Tags),
+			BaseUrl:      site.BaseUrl,
+			Url:          post.Url,
+			Image:        post.Image,
+			Prism:        true,
+			CanonicalUrl: post.CanonicalUrl,
+			JsonLd:       blogPostJsonLd(site, post),
+			NoIndex:      post.Unlisted,
 		}
-		if hasAssets {
-			if err := os.CopyFS(fmt.Sprintf("./dist/blog/posts/%s/assets", post.ID), os.DirFS(fmt.Sprintf("./blog/%s/assets", post.ID))); err != nil {
-				return err
-			}
+		if post.Prev != nil {
+			metadata.PrevUrl = post.Prev.Url
 		}
-
-		for _, tag := range post.Tags {
-			if tagPosts, ok := tags[tag]; ok {
-				tags[tag] = append(tagPosts, post)
-			} else {
-				tags[tag] = []BlogPost{post}
-			}
+		if post.Next != nil {
+			metadata.NextUrl = post.Next.Url
 		}
+
+		p.Go(func() {
+			b.errs.Add(fmt.Sprintf("blog/%s/%s.md", post.ID, post.ID), "render", b.buildTemplate("blog-post", urlPath(post.Url), Data{
+				Metadata: metadata,
+				Content:  post,
+			}))
+
+			b.errs.Add(fmt.Sprintf("blog/%s/assets", post.ID), "assets", b.copyAssets(path.Join(urlPath(post.Url), "assets"), fmt.Sprintf("blog/%s/assets", post.ID)))
+		})
 	}
 
-	for key, val := range tags {
-		tagData := Data{
-			Metadata: Metadata{
-				Title:       fmt.Sprintf("%s - Blog - Rico Berger", key),
-				Description: fmt.Sprintf("Blog Posts about %s", key),
-				Author:      "Rico Berger",
-				Keywords:    []string{"Rico Berger", "Blog", key},
-				BaseUrl:     defaultBaseUrl,
-				Url:         fmt.Sprintf("/blog/tags/%s/", key),
-				Image:       defaultImage,
-				Prism:       false,
-			},
-			Content: BlogTag{
-				Tag:   key,
-				Posts: val,
-			},
+	for _, key := range slices.Sorted(maps.Keys(tags)) {
+		val := tags[key]
+		name := b.tagNames[key]
+		config := b.tagConfigs[key]
+
+		tagMetadata := Metadata{
+			Title:       site.title(name, site.Sections.Blog.ItemTitle),
+			Description: fmt.Sprintf("Blog Posts about %s", name),
+			Author:      site.Author,
+			Keywords:    append(slices.Clone(site.Sections.Blog.Keywords), name),
+			BaseUrl:     site.BaseUrl,
+			Url:         fmt.Sprintf("/blog/tags/%s/", key),
+			Image:       site.Sections.Blog.Image,
+			Prism:       false,
 		}
-
-		if err := buildTemplate("blog-tag", fmt.Sprintf("./dist/blog/tags/%s", key), tagData); err != nil {
-			return err
+		if config.Description != "" {
+			tagMetadata.Description = config.Description
+		}
+		if config.Image != "" {
+			tagMetadata.Image = config.Image
 		}
 
-		if err := buildRssFeed(fmt.Sprintf("./dist/blog/tags/%s", key), tagData.Metadata, val); err != nil {
-			return err
+		for _, page := range paginate(val, site.Pagination.Tags, tagMetadata.Url) {
+			metadata := tagMetadata
+			metadata.Title = site.title(append(pageTitle(page.Pager.Page, name), site.Sections.Blog.ItemTitle)...)
+			metadata.Url = page.Url
+			metadata.PrevUrl = page.Pager.PrevUrl
+			metadata.NextUrl = page.Pager.NextUrl
+
+			p.Go(func() {
+				b.errs.Add("templates/blog-tag.html", fmt.Sprintf("render %s", metadata.Url), b.buildTemplate("blog-tag", urlPath(page.Url), Data{
+					Metadata: metadata,
+					Content: BlogTag{
+						Tag:         name,
+						Slug:        key,
+						Description: config.Description,
+						Image:       config.Image,
+						Posts:       page.Posts,
+						Pager:       page.Pager,
+					},
+				}))
+			})
 		}
+
+		p.Go(func() {
+			b.errs.Add("blog", fmt.Sprintf("feed %sfeed.xml", tagMetadata.Url), b.buildRssFeed(urlPath(tagMetadata.Url), tagMetadata, val))
+		})
 	}
 
-	return nil
+	p.Go(func() {
+		b.errs.Add("templates/blog-tags.html", "render", b.buildTagIndex(tags))
+	})
+
+	b.buildArchive(p, posts)
+	b.buildSeries(p, series, posts)
+	b.buildAuthors(p, posts)
+	b.buildRedirects(p, slices.Concat(posts, unlisted))
+
+	p.Wait()
 }
 
-func buildRssFeed(distPath string, metadata Metadata, posts []BlogPost) error {
+// buildRssFeed builds the RSS feed for the given posts, when the posts or the
+// metadata changed since the last build.
+func (b *Builder) buildRssFeed(distPath string, metadata Metadata, posts []BlogPost) error {
+	hash, err := hashOf(b.site, metadata, posts)
+	if err != nil {
+		return err
+	}
+
+	return b.cache.Build(path.Join(distPath, "feed.xml"), hash, func() error {
+		return b.writeRssFeed(distPath, metadata, posts)
+	})
+}
+
+func (b *Builder) writeRssFeed(distPath string, metadata Metadata, posts []BlogPost) error {
+	site := b.site
+
 	var rssItems []*RssItem
 
 	for _, post := range posts {
-		doc, err := goquery.NewDocumentFromReader(strings.NewReader(string(post.Content)))
+		// The link to the post after the excerpt is relative, so that it is
+		// resolved like all other links of the post.
+		postContent := string(post.Content)
+		if site.Feed.Content == feedContentExcerpt && post.Excerpt != "" {
+			postContent = fmt.Sprintf("%s\n<p><a href=\"./\">Continue reading</a></p>", post.Excerpt)
+		}
+
+		// Errors of a single post are reported for the post, so that the post
+		// can be found, which breaks the feed.
+		postFile := fmt.Sprintf("blog/%s/%s.md", post.ID, post.ID)
+
+		doc, err := goquery.NewDocumentFromReader(strings.NewReader(postContent))
 		if err != nil {
-			return err
+			return &BuildError{File: postFile, Phase: "feed", Err: err}
 		}
 
+		// The anchor links of the headings are only useful on the page of the
+		// post, feed readers show them as text.
+		doc.Find(fmt.Sprintf("a.%s", headingAnchorClass)).Remove()
+
 		doc.Find("a").Each(func(i int, s *goquery.Selection) {
 			if href, ok := s.Attr("href"); ok {
 				if strings.HasPrefix(href, "./") || strings.HasPrefix(href, "/") {
-					base, err := url.Parse(fmt.Sprintf("%s/blog/posts/%s/", defaultBaseUrl, post.ID))
+					base, err := url.Parse(fmt.Sprintf("%s%s", site.BaseUrl, post.Url))
 					if err != nil {
 						return
 					}
@@ -638,7 +1112,7 @@ func buildRssFeed(distPath string, metadata Metadata, posts []BlogPost) error {
 		doc.Find("img").Each(func(i int, s *goquery.Selection) {
 			if src, ok := s.Attr("src"); ok {
 				if strings.HasPrefix(src, "./") || strings.HasPrefix(src, "/") {
-					base, err := url.Parse(fmt.Sprintf("%s/blog/posts/%s/", defaultBaseUrl, post.ID))
+					base, err := url.Parse(fmt.Sprintf("%s%s", site.BaseUrl, post.Url))
 					if err != nil {
 						return
 					}
@@ -653,48 +1127,60 @@ func buildRssFeed(distPath string, metadata Metadata, posts []BlogPost) error {
 
 		content, err := doc.Find("body").Html()
 		if err != nil {
-			return err
+			return &BuildError{File: postFile, Phase: "feed", Err: err}
 		}
 
 		rssItems = append(rssItems, &RssItem{
 			Title: post.Title,
-			Link:  fmt.Sprintf("%s/blog/posts/%s/", defaultBaseUrl, post.ID),
+			Link:  fmt.Sprintf("%s%s", site.BaseUrl, post.Url),
 			Description: &RssDescription{
 				Content: content,
 			},
-			Author: post.AuthorName,
+			Author:   post.Authors[0].rssAuthor(),
+			Creators: authorNames(post.Authors),
 			Enclosure: &RssEnclosure{
-				Url:  fmt.Sprintf("%s%s", defaultBaseUrl, post.Image),
+				Url:  fmt.Sprintf("%s%s", site.BaseUrl, post.Image),
 				Type: mime.TypeByExtension(filepath.Ext(post.Image)),
 			},
 			Guid: &RssGuid{
-				Id:          fmt.Sprintf("%s/blog/posts/%s/", defaultBaseUrl, post.ID),
+				Id:          fmt.Sprintf("%s%s", site.BaseUrl, post.Url),
 				IsPermaLink: "true",
 			},
 			PubDate: post.PublishedAt.Format(time.RFC1123Z),
+			Updated: post.UpdatedAt.Format(time.RFC3339),
+			AtomLinks: []*AtomLink{
+				{Rel: "canonical", Href: post.CanonicalUrl},
+			},
 		})
 	}
 
+	// The feed is only changed, when a new post is published, so that the
+	// feed is the same for every build of the same content. A feed without
+	// posts uses the build date.
+	pubDate := latest(b.buildDate, newestPost(posts)).Format(time.RFC1123Z)
+
 	rssFeed := RssFeedXml{
 		Version: "2.0",
 		Channel: &RssFeed{
 			Title:         metadata.Title,
-			Link:          fmt.Sprintf("%s%s", defaultBaseUrl, metadata.Url),
+			Link:          fmt.Sprintf("%s%s", site.BaseUrl, metadata.Url),
 			Description:   metadata.Description,
-			Language:      "en-us",
-			Copyright:     "Rico Berger",
-			PubDate:       time.Now().Format(time.RFC1123Z),
-			LastBuildDate: time.Now().Format(time.RFC1123Z),
+			Language:      site.Feed.Language,
+			Copyright:     site.Feed.Copyright,
+			PubDate:       pubDate,
+			LastBuildDate: pubDate,
 			Image: &RssImage{
-				Url:    fmt.Sprintf("%s/assets/img/icons/icon.png", defaultBaseUrl),
-				Title:  "Rico Berger",
-				Link:   defaultBaseUrl,
-				Width:  1024,
-				Height: 1024,
+				Url:    fmt.Sprintf("%s%s", site.BaseUrl, site.Feed.Icon.Url),
+				Title:  site.Title,
+				Link:   site.BaseUrl,
+				Width:  site.Feed.Icon.Width,
+				Height: site.Feed.Icon.Height,
 			},
 			Items: rssItems,
 		},
 		ContentNamespace: "http://purl.org/rss/1.0/modules/content/",
+		AtomNamespace:    "http://www.w3.org/2005/Atom",
+		DcNamespace:      "http://purl.org/dc/elements/1.1/",
 	}
 
 	data, err := xml.Marshal(rssFeed)
@@ -702,76 +1188,154 @@ func buildRssFeed(distPath string, metadata Metadata, posts []BlogPost) error {
 		return err
 	}
 
-	err = os.WriteFile(fmt.Sprintf("%s/feed.xml", distPath), data, 0600)
-	if err != nil {
-		return err
-	}
+	return b.out.WriteFile(path.Join(distPath, "feed.xml"), data)
+}
 
-	return nil
+func (b *Builder) buildSitemap() {
+	b.errs.Add("", "sitemap", b.buildSitemapXml())
 }
 
-func buildSitemap() error {
-	lastMod := time.Now().Format("2006-01-02")
+func (b *Builder) buildSitemapXml() error {
+	site := b.site
+
+	// The "lastmod" date of a page is the time of the last commit of its
+	// sources and of a post its "UpdatedAt" date. If the sources are not in a
+	// git repository, the build time is used for all other pages.
+	lastMod := func(times ...time.Time) string {
+		return latest(b.buildDate, times...).In(site.location).Format(time.DateOnly)
+	}
 
 	sitemapItems := []*SitemapItem{
 		{
-			Loc:        defaultBaseUrl,
-			LastMod:    lastMod,
+			Loc:        site.BaseUrl,
+			LastMod:    lastMod(b.lastModified("templates/home.html")),
 			ChangeFreq: "daily",
 			Priority:   "1.0",
 		},
 		{
-			Loc:        fmt.Sprintf("%s/about/", defaultBaseUrl),
-			LastMod:    lastMod,
+			Loc:        fmt.Sprintf("%s/about/", site.BaseUrl),
+			LastMod:    lastMod(b.lastModified("templates/about.html")),
 			ChangeFreq: "daily",
 			Priority:   "1.0",
 		},
 		{
-			Loc:        fmt.Sprintf("%s/blog/", defaultBaseUrl),
-			LastMod:    lastMod,
-			ChangeFreq: "daily",
-			Priority:   "1.0",
-		},
-		{
-			Loc:        fmt.Sprintf("%s/cheat-sheets/", defaultBaseUrl),
-			LastMod:    lastMod,
+			Loc:        fmt.Sprintf("%s/cheat-sheets/", site.BaseUrl),
+			LastMod:    lastMod(b.lastModified("templates/cheat-sheets.html", "cheat-sheets")),
 			ChangeFreq: "daily",
 			Priority:   "1.0",
 		},
 	}
 
-	posts, err := os.ReadDir("./dist/blog/posts")
-	if err != nil {
-		return err
+	// All pages of the paginated lists are added, so that every post can be
+	// found via the lists. The first page of the blog keeps its priority.
+	for _, page := range paginate(b.posts, site.Pagination.Blog, "/blog/") {
+		priority := "0.5"
+		if page.Pager.Page == 1 {
+			priority = "1.0"
+		}
+
+		sitemapItems = append(sitemapItems, &SitemapItem{
+			Loc:        fmt.Sprintf("%s%s", site.BaseUrl, page.Url),
+			LastMod:    lastMod(b.lastModified("templates/blog.html"), newestPost(page.Posts)),
+			ChangeFreq: "daily",
+			Priority:   priority,
+		})
 	}
 
-	for _, post := range posts {
-		if post.IsDir() {
+	sitemapItems = append(sitemapItems, &SitemapItem{
+		Loc:        fmt.Sprintf("%s/blog/archive/", site.BaseUrl),
+		LastMod:    lastMod(b.lastModified("templates/blog-archive.html"), newestPost(b.posts)),
+		ChangeFreq: "weekly",
+		Priority:   "0.3",
+	})
+
+	for _, year := range archiveYears(b.posts, site.location) {
+		sitemapItems = append(sitemapItems, &SitemapItem{
+			Loc:        fmt.Sprintf("%s%s", site.BaseUrl, year.Url),
+			LastMod:    lastMod(b.lastModified("templates/blog-archive.html"), newestPost(year.Posts)),
+			ChangeFreq: "weekly",
+			Priority:   "0.3",
+		})
+
+		for _, month := range year.Months {
 			sitemapItems = append(sitemapItems, &SitemapItem{
-				Loc:        fmt.Sprintf("%s/blog/posts/%s/", defaultBaseUrl, post.Name()),
-				LastMod:    lastMod,
+				Loc:        fmt.Sprintf("%s%s", site.BaseUrl, month.Url),
+				LastMod:    lastMod(b.lastModified("templates/blog-archive.html"), newestPost(month.Posts)),
 				ChangeFreq: "monthly",
-				Priority:   "0.5",
+				Priority:   "0.3",
 			})
 		}
 	}
 
-	cheatSheets, err := os.ReadDir("./dist/cheat-sheets")
-	if err != nil {
-		return err
+	sitemapItems = append(sitemapItems, &SitemapItem{
+		Loc:        fmt.Sprintf("%s/blog/tags/", site.BaseUrl),
+		LastMod:    lastMod(b.lastModified("templates/blog-tags.html"), newestPost(b.posts)),
+		ChangeFreq: "weekly",
+		Priority:   "0.3",
+	})
+
+	for _, s := range b.series {
+		sitemapItems = append(sitemapItems, &SitemapItem{
+			Loc:        fmt.Sprintf("%s%s", site.BaseUrl, s.Url),
+			LastMod:    lastMod(b.lastModified("templates/blog-series.html"), s.newestPost()),
+			ChangeFreq: "weekly",
+			Priority:   "0.3",
+		})
 	}
 
-	for _, post := range cheatSheets {
-		if post.IsDir() {
+	for _, key := range slices.Sorted(maps.Keys(b.tags)) {
+		for _, page := range paginate(b.tags[key], site.Pagination.Tags, fmt.Sprintf("/blog/tags/%s/", key)) {
 			sitemapItems = append(sitemapItems, &SitemapItem{
-				Loc:        fmt.Sprintf("%s/cheat-sheets/%s/", defaultBaseUrl, post.Name()),
-				LastMod:    lastMod,
+				Loc:        fmt.Sprintf("%s%s", site.BaseUrl, page.Url),
+				LastMod:    lastMod(b.lastModified("templates/blog-tag.html"), newestPost(page.Posts)),
 				ChangeFreq: "weekly",
-				Priority:   "0.5",
+				Priority:   "0.3",
 			})
 		}
 	}
 
+	for _, id := range slices.Sorted(maps.Keys(b.authorConfigs)) {
+		for _, page := range paginate(postsByAuthor(b.posts, id), site.Pagination.Authors, fmt.Sprintf("/blog/authors/%s/", id)) {
+			sitemapItems = append(sitemapItems, &SitemapItem{
+				Loc:        fmt.Sprintf("%s%s", site.BaseUrl, page.Url),
+				LastMod:    lastMod(b.lastModified("templates/blog-author.html", authorsFile), newestPost(page.Posts)),
+				ChangeFreq: "weekly",
+				Priority:   "0.3",
+			})
+		}
+	}
+
+	// The posts and cheat sheets are taken from the build and not from the dist
+	// folder, so that drafts and scheduled posts from a previous preview build
+	// are never added to the sitemap.
+	posts := slices.SortedFunc(slices.Values(b.posts), func(a, b BlogPost) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+
+	for _, post := range posts {
+		// Posts, which were published on another site first, are not added,
+		// because the sitemap should only contain canonical urls.
+		if post.CanonicalUrl != fmt.Sprintf("%s%s", site.BaseUrl, post.Url) {
+			continue
+		}
+
+		sitemapItems = append(sitemapItems, &SitemapItem{
+			Loc:        fmt.Sprintf("%s%s", site.BaseUrl, post.Url),
+			LastMod:    lastMod(post.UpdatedAt),
+			ChangeFreq: "monthly",
+			Priority:   "0.5",
+		})
+	}
+
+	for _, cheatSheet := range b.cheatSheets {
+		sitemapItems = append(sitemapItems, &SitemapItem{
+			Loc:        fmt.Sprintf("%s/cheat-sheets/%s/", site.BaseUrl, cheatSheet.ID),
+			LastMod:    lastMod(b.lastModified(fmt.Sprintf("cheat-sheets/%s", cheatSheet.ID))),
+			ChangeFreq: "weekly",
+			Priority:   "0.5",
+		})
+	}
+
 	data, err := xml.Marshal(Sitemap{
 		Xmlns: "http://www.sitemaps.org/schemas/sitemap/0.9",
 		URL:   sitemapItems,
@@ -780,12 +1344,14 @@ func buildSitemap() error {
 		return err
 	}
 
-	err = os.WriteFile("./dist/sitemap.xml", data, 0600)
+	hash, err := hashOf(data)
 	if err != nil {
 		return err
 	}
 
-	return nil
+	return b.cache.Build("sitemap.xml", hash, func() error {
+		return b.out.WriteFile("sitemap.xml", data)
+	})
 }
 
 type ImageExtender struct{}
@@ -0,0 +1,293 @@
+package main
+
+import (
+	"io/fs"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// testTemplate is used for all pages of the test site, except for the blog
+// index and the blog posts, so that the tests do not depend on the real
+// templates.
+const testTemplate = `{{ define "content" }}{{ .Metadata.Url }}{{ end }}`
+
+// testSite returns a small site with two published posts and a draft.
+func testSite() fstest.MapFS {
+	site := fstest.MapFS{
+		"site.yaml": {Data: []byte(`title: Test
+author: Jane Doe
+baseUrl: https://example.com
+timezone: UTC
+feed:
+  language: en-us
+sections:
+  blog:
+    title: Blog
+`)},
+		"templates/base.html":      {Data: []byte(`<title>{{ .Metadata.Title }}</title>{{ block "content" . }}{{ end }}`)},
+		"templates/blog.html":      {Data: []byte(`{{ define "content" }}{{ range .Content.Posts }}<a href="{{ .Url }}">{{ .Title }}</a>{{ .Excerpt }}{{ end }}{{ end }}`)},
+		"templates/blog-post.html": {Data: []byte(`{{ define "content" }}<h1>{{ .Content.Title }}</h1>{{ .Content.Content }}{{ end }}`)},
+		"cheat-sheets/.keep":       {Data: nil},
+		"blog/authors.yaml": {Data: []byte(`jane-doe:
+  name: Jane Doe
+`)},
+
+		"blog/first-post/first-post.md": {Data: []byte(`---
+Title: First Post
+Description: The first post.
+AuthorName: Jane Doe
+AuthorTitle: Engineer
+AuthorImage: /jane.webp
+PublishedAt: 2025-01-01 12:00:00
+Tags:
+  - go
+---
+
+Hello **world**.
+`)},
+		"blog/second-post/second-post.md": {Data: []byte(`---
+Title: Second Post
+Description: The second post.
+AuthorName: Jane Doe
+AuthorTitle: Engineer
+AuthorImage: /jane.webp
+PublishedAt: 2025-02-01 12:00:00
+Tags:
+  - go
+  - testing
+---
+
+## Introduction
+
+Second content.
+
+<!--more-->
+
+More content.
+`)},
+		"blog/draft-post/draft-post.md": {Data: []byte(`---
+Title: Draft Post
+Description: A draft.
+AuthorName: Jane Doe
+AuthorTitle: Engineer
+AuthorImage: /jane.webp
+PublishedAt: 2025-03-01 12:00:00
+Tags:
+  - go
+Draft: true
+---
+
+Not finished.
+`)},
+	}
+
+	for _, tmpl := range []string{"home", "about", "analytics", "404", "cheat-sheets", "cheat-sheet", "blog-tag", "blog-tags", "blog-archive", "blog-series", "blog-author"} {
+		site["templates/"+tmpl+".html"] = &fstest.MapFile{Data: []byte(testTemplate)}
+	}
+
+	return site
+}
+
+// buildTestSite builds the site from the file system into the output. If the
+// cache directory isn't empty, the build uses the build cache from this
+// directory.
+func buildTestSite(t *testing.T, src fs.FS, out *MemOutput, cacheDir string) {
+	t.Helper()
+
+	site, err := loadSite(src, "site.yaml")
+	if err != nil {
+		t.Fatalf("failed to load site: %v", err)
+	}
+
+	b := newSiteBuilder(site, src, out, BuildOptions{Jobs: 2})
+	if cacheDir != "" {
+		b.cache, err = loadCache(cacheDir, "dist", out, false)
+		if err != nil {
+			t.Fatalf("failed to load cache: %v", err)
+		}
+	}
+
+	if err := b.build(); err != nil {
+		t.Fatalf("build failed: %v", err)
+	}
+}
+
+func readOutput(t *testing.T, out *MemOutput, name string) string {
+	t.Helper()
+
+	data, err := fs.ReadFile(out.FS(), name)
+	if err != nil {
+		t.Fatalf("failed to read %s: %v", name, err)
+	}
+	return string(data)
+}
+
+func TestBuild(t *testing.T) {
+	out := newMemOutput()
+	buildTestSite(t, testSite(), out, "")
+
+	t.Run("pages", func(t *testing.T) {
+		for _, name := range []string{
+			"index.html",
+			"404.html",
+			"blog/index.html",
+			"blog/posts/first-post/index.html",
+			"blog/posts/second-post/index.html",
+			"blog/tags/index.html",
+			"blog/tags/go/index.html",
+			"blog/tags/testing/index.html",
+			"blog/2025/index.html",
+			"sitemap.xml",
+		} {
+			if _, err := out.Stat(name); err != nil {
+				t.Errorf("missing %s: %v", name, err)
+			}
+		}
+	})
+
+	t.Run("post", func(t *testing.T) {
+		post := readOutput(t, out, "blog/posts/first-post/index.html")
+		if !strings.Contains(post, "<h1>First Post</h1>") || !strings.Contains(post, "<strong>world</strong>") {
+			t.Errorf("unexpected post page: %s", post)
+		}
+	})
+
+	t.Run("index", func(t *testing.T) {
+		index := readOutput(t, out, "blog/index.html")
+		second, first := strings.Index(index, "Second Post"), strings.Index(index, "First Post")
+		if first < 0 || second < 0 || second > first {
+			t.Errorf("expected the posts sorted by their publish date: %s", index)
+		}
+	})
+
+	t.Run("heading anchors", func(t *testing.T) {
+		if post := readOutput(t, out, "blog/posts/second-post/index.html"); !strings.Contains(post, `<a class="heading-anchor" href="#introduction"`) {
+			t.Errorf("missing heading anchor in post page: %s", post)
+		}
+		if index := readOutput(t, out, "blog/index.html"); !strings.Contains(index, `<h2>Introduction</h2>`) {
+			t.Errorf("heading anchor or id in excerpt: %s", index)
+		}
+		if feed := readOutput(t, out, "blog/feed.xml"); strings.Contains(feed, "heading-anchor") {
+			t.Errorf("heading anchor in feed: %s", feed)
+		}
+	})
+
+	t.Run("redirects", func(t *testing.T) {
+		if _, err := out.Stat(redirectsFile); err == nil {
+			t.Errorf("%s was written without aliases", redirectsFile)
+		}
+	})
+
+	t.Run("drafts", func(t *testing.T) {
+		if _, err := out.Stat("blog/posts/draft-post/index.html"); err == nil {
+			t.Error("draft post was built")
+		}
+		if strings.Contains(readOutput(t, out, "blog/index.html"), "Draft Post") {
+			t.Error("draft post is listed on the blog index")
+		}
+	})
+
+	t.Run("feeds", func(t *testing.T) {
+		feed := readOutput(t, out, "blog/feed.xml")
+		for _, want := range []string{
+			"<link>https://example.com/blog/posts/first-post/</link>",
+			"<link>https://example.com/blog/posts/second-post/</link>",
+			"<p>Hello <strong>world</strong>.</p>",
+		} {
+			if !strings.Contains(feed, want) {
+				t.Errorf("blog feed doesn't contain %q: %s", want, feed)
+			}
+		}
+		if strings.Contains(feed, "Draft Post") {
+			t.Error("draft post is in the blog feed")
+		}
+
+		tagFeed := readOutput(t, out, "blog/tags/testing/feed.xml")
+		if !strings.Contains(tagFeed, "Second Post") || strings.Contains(tagFeed, "First Post") {
+			t.Errorf("unexpected tag feed: %s", tagFeed)
+		}
+
+		authorFeed := readOutput(t, out, "blog/authors/jane-doe/feed.xml")
+		if !strings.Contains(authorFeed, "First Post") || !strings.Contains(authorFeed, "Second Post") {
+			t.Errorf("expected the posts with the author name in the author feed: %s", authorFeed)
+		}
+	})
+}
+
+func TestBuildRenamedPost(t *testing.T) {
+	src := testSite()
+	out := newMemOutput()
+	cacheDir := t.TempDir()
+
+	buildTestSite(t, src, out, cacheDir)
+
+	// The first post is renamed and keeps its old url as alias. The second
+	// build must not remove the redirect page at the old url, which was the
+	// page of the post in the first build.
+	src["blog/first-post/first-post.md"].Data = []byte(strings.Replace(string(src["blog/first-post/first-post.md"].Data), "Title: First Post\n", "Title: First Post\nSlug: renamed-post\nAliases:\n  - /blog/posts/first-post/\n", 1))
+
+	buildTestSite(t, src, out, cacheDir)
+
+	if post := readOutput(t, out, "blog/posts/renamed-post/index.html"); !strings.Contains(post, "<h1>First Post</h1>") {
+		t.Errorf("unexpected post page: %s", post)
+	}
+	if redirect := readOutput(t, out, "blog/posts/first-post/index.html"); !strings.Contains(redirect, `content="0; url=https://example.com/blog/posts/renamed-post/"`) {
+		t.Errorf("expected a redirect page at the old url: %s", redirect)
+	}
+	if redirects := readOutput(t, out, redirectsFile); redirects != "/blog/posts/first-post/ /blog/posts/renamed-post/ 301\n" {
+		t.Errorf("unexpected redirects: %q", redirects)
+	}
+
+	// A third build without changes must keep all files.
+	buildTestSite(t, src, out, cacheDir)
+
+	if _, err := out.Stat("blog/posts/first-post/index.html"); err != nil {
+		t.Errorf("redirect page was removed: %v", err)
+	}
+
+	// The aliases of unlisted posts are not added to the redirects, so that
+	// the old "_redirects" file must be removed.
+	src["blog/first-post/first-post.md"].Data = []byte(strings.Replace(string(src["blog/first-post/first-post.md"].Data), "Title: First Post\n", "Title: First Post\nUnlisted: true\n", 1))
+
+	buildTestSite(t, src, out, cacheDir)
+
+	if _, err := out.Stat(redirectsFile); err == nil {
+		t.Errorf("%s wasn't removed", redirectsFile)
+	}
+	if _, err := out.Stat("blog/posts/first-post/index.html"); err != nil {
+		t.Errorf("redirect page of unlisted post was removed: %v", err)
+	}
+}
+
+func TestBuildAssets(t *testing.T) {
+	src := testSite()
+	src["templates/assets/css/input.css"] = &fstest.MapFile{Data: []byte("body {}")}
+	src["templates/assets/img/logo.png"] = &fstest.MapFile{Data: []byte("logo")}
+
+	out := newMemOutput()
+	cacheDir := t.TempDir()
+
+	buildTestSite(t, src, out, cacheDir)
+
+	// The generated CSS file is written into the assets directory after the
+	// build, e.g. by Tailwind. It must be kept, when the assets change.
+	if err := out.WriteFile("assets/css/output.css", []byte("generated")); err != nil {
+		t.Fatal(err)
+	}
+
+	src["templates/assets/css/input.css"].Data = []byte("body { margin: 0; }")
+	delete(src, "templates/assets/img/logo.png")
+
+	buildTestSite(t, src, out, cacheDir)
+
+	if css := readOutput(t, out, "assets/css/input.css"); css != "body { margin: 0; }" {
+		t.Errorf("changed asset wasn't copied: %q", css)
+	}
+	if css := readOutput(t, out, "assets/css/output.css"); css != "generated" {
+		t.Errorf("unexpected generated file: %q", css)
+	}
+	if _, err := out.Stat("assets/img/logo.png"); err == nil {
+		t.Error("removed asset wasn't removed from the output")
+	}
+}
@@ -0,0 +1,56 @@
+package main
+
+// BlogPostTagNavigation links to the previous and next post with the tag. The
+// links are nil for the first and last post of the tag.
+type BlogPostTagNavigation struct {
+	Tag  BlogPostTag
+	Prev *BlogPostLink
+	Next *BlogPostLink
+}
+
+// linkPosts sets the previous and next post of every post, chronologically and
+// for every tag of the post. The previous post is the older one and the next
+// post is the newer one. The posts must be sorted by their publish date,
+// starting with the newest post.
+func linkPosts(posts []BlogPost) {
+	tagPosts := make(map[string][]int)
+
+	for i := range posts {
+		if i+1 < len(posts) {
+			link := newBlogPostLink(posts[i+1])
+			posts[i].Prev = &link
+		}
+		if i > 0 {
+			link := newBlogPostLink(posts[i-1])
+			posts[i].Next = &link
+		}
+
+		for _, tag := range posts[i].Tags {
+			tagPosts[tag.Slug] = append(tagPosts[tag.Slug], i)
+		}
+	}
+
+	for i := range posts {
+		for _, tag := range posts[i].Tags {
+			indexes := tagPosts[tag.Slug]
+			navigation := BlogPostTagNavigation{Tag: tag}
+
+			for j, index := range indexes {
+				if index != i {
+					continue
+				}
+
+				if j+1 < len(indexes) {
+					link := newBlogPostLink(posts[indexes[j+1]])
+					navigation.Prev = &link
+				}
+				if j > 0 {
+					link := newBlogPostLink(posts[indexes[j-1]])
+					navigation.Next = &link
+				}
+			}
+
+			posts[i].TagNavigation = append(posts[i].TagNavigation, navigation)
+		}
+	}
+}
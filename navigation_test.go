@@ -0,0 +1,57 @@
+package main
+
+import "testing"
+
+// linkID returns the id of the linked post or an empty string, if the link is
+// nil.
+func linkID(link *BlogPostLink) string {
+	if link == nil {
+		return ""
+	}
+	return link.ID
+}
+
+func TestLinkPosts(t *testing.T) {
+	posts := []BlogPost{
+		testPost("p1", "2025-04-01", "go"),
+		testPost("p2", "2025-03-01", "kubernetes"),
+		testPost("p3", "2025-02-01", "go", "kubernetes"),
+		testPost("p4", "2025-01-01", "go"),
+	}
+	linkPosts(posts)
+
+	for _, tt := range []struct {
+		id   string
+		prev string
+		next string
+		tags map[string][2]string
+	}{
+		{id: "p1", prev: "p2", next: "", tags: map[string][2]string{"go": {"p3", ""}}},
+		{id: "p2", prev: "p3", next: "p1", tags: map[string][2]string{"kubernetes": {"p3", ""}}},
+		{id: "p3", prev: "p4", next: "p2", tags: map[string][2]string{"go": {"p4", "p1"}, "kubernetes": {"", "p2"}}},
+		{id: "p4", prev: "", next: "p3", tags: map[string][2]string{"go": {"", "p3"}}},
+	} {
+		t.Run(tt.id, func(t *testing.T) {
+			var post BlogPost
+			for _, p := range posts {
+				if p.ID == tt.id {
+					post = p
+				}
+			}
+
+			if prev, next := linkID(post.Prev), linkID(post.Next); prev != tt.prev || next != tt.next {
+				t.Errorf("expected prev %q and next %q, got %q and %q", tt.prev, tt.next, prev, next)
+			}
+
+			if len(post.TagNavigation) != len(tt.tags) {
+				t.Fatalf("expected navigation for %d tags, got %d", len(tt.tags), len(post.TagNavigation))
+			}
+			for _, navigation := range post.TagNavigation {
+				want := tt.tags[navigation.Tag.Slug]
+				if prev, next := linkID(navigation.Prev), linkID(navigation.Next); prev != want[0] || next != want[1] {
+					t.Errorf("tag %s: expected prev %q and next %q, got %q and %q", navigation.Tag.Slug, want[0], want[1], prev, next)
+				}
+			}
+		})
+	}
+}
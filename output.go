@@ -0,0 +1,123 @@
+package main
+
+import (
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing/fstest"
+)
+
+// Output is the destination of a build. All names are slash-separated paths
+// relative to the root of the output, as for an fs.FS, so that a build can be
+// written into a directory or into memory.
+type Output interface {
+	// WriteFile writes the data to the named file. Missing parent directories
+	// are created.
+	WriteFile(name string, data []byte) error
+	// Remove removes the named file or directory with all its children.
+	Remove(name string) error
+	Stat(name string) (fs.FileInfo, error)
+}
+
+// DirOutput writes the build into a directory on disk.
+type DirOutput struct {
+	dir string
+}
+
+func newDirOutput(dir string) *DirOutput {
+	return &DirOutput{dir: dir}
+}
+
+func (o *DirOutput) path(name string) string {
+	return filepath.Join(o.dir, filepath.FromSlash(name))
+}
+
+func (o *DirOutput) WriteFile(name string, data []byte) error {
+	file := o.path(name)
+
+	if err := os.MkdirAll(filepath.Dir(file), os.ModePerm); err != nil {
+		return err
+	}
+
+	// The files of the site are public, so that they must be readable by the
+	// web server.
+	// #nosec G306
+	return os.WriteFile(file, data, 0644)
+}
+
+// Remove removes the named file or directory and all parent directories within
+// the output directory, which are empty afterwards.
+func (o *DirOutput) Remove(name string) error {
+	file := o.path(name)
+	if err := os.RemoveAll(file); err != nil {
+		return err
+	}
+
+	root := filepath.Clean(o.dir)
+	for dir := filepath.Dir(file); dir != root && strings.HasPrefix(dir, root); dir = filepath.Dir(dir) {
+		if err := os.Remove(dir); err != nil {
+			break
+		}
+	}
+
+	return nil
+}
+
+func (o *DirOutput) Stat(name string) (fs.FileInfo, error) {
+	return os.Stat(o.path(name))
+}
+
+// MemOutput keeps the build in memory, e.g. to build a site from an
+// fstest.MapFS without touching the disk. It is safe to write files from
+// multiple goroutines.
+type MemOutput struct {
+	mu    sync.Mutex
+	files fstest.MapFS
+}
+
+func newMemOutput() *MemOutput {
+	return &MemOutput{files: make(fstest.MapFS)}
+}
+
+func (o *MemOutput) WriteFile(name string, data []byte) error {
+	if !fs.ValidPath(name) {
+		return &fs.PathError{Op: "write", Path: name, Err: fs.ErrInvalid}
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.files[name] = &fstest.MapFile{Data: append([]byte(nil), data...), Mode: 0644}
+	return nil
+}
+
+func (o *MemOutput) Remove(name string) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	for file := range o.files {
+		if file == name || strings.HasPrefix(file, name+"/") {
+			delete(o.files, file)
+		}
+	}
+	return nil
+}
+
+func (o *MemOutput) Stat(name string) (fs.FileInfo, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	return fs.Stat(o.files, name)
+}
+
+// FS returns the files, which were written so far. Later writes are not
+// visible in the returned file system.
+func (o *MemOutput) FS() fs.FS {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	return maps.Clone(o.files)
+}
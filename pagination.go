@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Pager is passed to the templates of paginated lists, so that they can link
+// to the previous and next page. The "PrevUrl" is empty on the first page and
+// the "NextUrl" is empty on the last page.
+type Pager struct {
+	Page       int
+	TotalPages int
+	PrevUrl    string
+	NextUrl    string
+}
+
+// BlogPage is a single page of a paginated list of blog posts.
+type BlogPage struct {
+	Url   string
+	Posts []BlogPost
+	Pager Pager
+}
+
+// paginate splits the posts into pages with the given number of posts. The
+// first page is available at the given url, all other pages at
+// "<url>page/<n>/". If the size is zero or negative, all posts are on the
+// first page. There is always at least one page, so that the list is also
+// built when there are no posts.
+func paginate(posts []BlogPost, size int, url string) []BlogPage {
+	if size <= 0 || len(posts) == 0 {
+		size = max(len(posts), 1)
+	}
+
+	totalPages := (len(posts) + size - 1) / size
+	totalPages = max(totalPages, 1)
+
+	pages := make([]BlogPage, 0, totalPages)
+	for i := range totalPages {
+		page := i + 1
+		pager := Pager{Page: page, TotalPages: totalPages}
+		if page > 1 {
+			pager.PrevUrl = pageUrl(url, page-1)
+		}
+		if page < totalPages {
+			pager.NextUrl = pageUrl(url, page+1)
+		}
+
+		pages = append(pages, BlogPage{
+			Url:   pageUrl(url, page),
+			Posts: posts[i*size : min((i+1)*size, len(posts))],
+			Pager: pager,
+		})
+	}
+
+	return pages
+}
+
+// pageUrl returns the url of the given page of the list with the url.
+func pageUrl(url string, page int) string {
+	if page == 1 {
+		return url
+	}
+	return fmt.Sprintf("%spage/%d/", url, page)
+}
+
+// pageTitle returns the title parts for the given page. The page number is only
+// added after the first page, so that the title of the first page doesn't
+// change.
+func pageTitle(page int, parts ...string) []string {
+	if page == 1 {
+		return parts
+	}
+	return append(parts, fmt.Sprintf("Page %d", page))
+}
+
+// urlPath returns the path of the directory in the output for the given url.
+func urlPath(url string) string {
+	return strings.Trim(url, "/")
+}
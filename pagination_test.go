@@ -0,0 +1,86 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+)
+
+// testPosts returns the given number of posts with the ids "post-1" to
+// "post-<n>".
+func testPosts(n int) []BlogPost {
+	posts := make([]BlogPost, 0, n)
+	for i := range n {
+		posts = append(posts, BlogPost{ID: fmt.Sprintf("post-%d", i+1)})
+	}
+	return posts
+}
+
+func postIDs(posts []BlogPost) []string {
+	ids := make([]string, 0, len(posts))
+	for _, post := range posts {
+		ids = append(ids, post.ID)
+	}
+	return ids
+}
+
+func TestPaginate(t *testing.T) {
+	for _, tt := range []struct {
+		name  string
+		posts int
+		size  int
+		pages []BlogPage
+	}{
+		{
+			name:  "no posts",
+			posts: 0,
+			size:  2,
+			pages: []BlogPage{
+				{Url: "/blog/", Pager: Pager{Page: 1, TotalPages: 1}},
+			},
+		},
+		{
+			name:  "size zero",
+			posts: 3,
+			size:  0,
+			pages: []BlogPage{
+				{Url: "/blog/", Posts: testPosts(3), Pager: Pager{Page: 1, TotalPages: 1}},
+			},
+		},
+		{
+			name:  "exact pages",
+			posts: 4,
+			size:  2,
+			pages: []BlogPage{
+				{Url: "/blog/", Posts: testPosts(4)[0:2], Pager: Pager{Page: 1, TotalPages: 2, NextUrl: "/blog/page/2/"}},
+				{Url: "/blog/page/2/", Posts: testPosts(4)[2:4], Pager: Pager{Page: 2, TotalPages: 2, PrevUrl: "/blog/"}},
+			},
+		},
+		{
+			name:  "partial last page",
+			posts: 5,
+			size:  2,
+			pages: []BlogPage{
+				{Url: "/blog/", Posts: testPosts(5)[0:2], Pager: Pager{Page: 1, TotalPages: 3, NextUrl: "/blog/page/2/"}},
+				{Url: "/blog/page/2/", Posts: testPosts(5)[2:4], Pager: Pager{Page: 2, TotalPages: 3, PrevUrl: "/blog/", NextUrl: "/blog/page/3/"}},
+				{Url: "/blog/page/3/", Posts: testPosts(5)[4:5], Pager: Pager{Page: 3, TotalPages: 3, PrevUrl: "/blog/page/2/"}},
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			pages := paginate(testPosts(tt.posts), tt.size, "/blog/")
+
+			if len(pages) != len(tt.pages) {
+				t.Fatalf("expected %d pages, got %d", len(tt.pages), len(pages))
+			}
+			for i, page := range pages {
+				if page.Url != tt.pages[i].Url || page.Pager != tt.pages[i].Pager {
+					t.Errorf("page %d: expected %s %+v, got %s %+v", i+1, tt.pages[i].Url, tt.pages[i].Pager, page.Url, page.Pager)
+				}
+				if ids, expected := postIDs(page.Posts), postIDs(tt.pages[i].Posts); !slices.Equal(ids, expected) {
+					t.Errorf("page %d: expected posts %v, got %v", i+1, expected, ids)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// defaultPermalink is the url of the blog posts, when it isn't set in the site
+// configuration.
+const defaultPermalink = "/blog/posts/:slug/"
+
+// redirectsFile is the file with the redirects from the aliases of the posts to
+// their url. It is used by hosts which support redirects, e.g. Netlify or
+// Cloudflare Pages. All other hosts use the redirect pages.
+const redirectsFile = "_redirects"
+
+// reservedUrls are the urls of the generated pages, which can not be used as
+// url or alias of a post.
+var reservedUrls = []string{"/about/", "/analytics/", "/blog/"}
+
+// reservedUrlPrefixes are the urls of the generated sections, which can not be
+// used as url or alias of a post, including all urls below them, e.g. the
+// pages of all tags below "/blog/tags/".
+var reservedUrlPrefixes = []string{"/assets/", "/cheat-sheets/", "/blog/page/", "/blog/tags/", "/blog/series/", "/blog/authors/", "/blog/archive/"}
+
+// archiveUrlRegexp matches the urls of the year and month archive pages, e.g.
+// "/blog/2025/" and "/blog/2025/06/".
+var archiveUrlRegexp = regexp.MustCompile(`^/blog/[0-9]+/([0-9]+/)?$`)
+
+// redirectTemplate is the page, which is written for every alias of a post, so
+// that the old links of a post keep working on hosts without redirects.
+var redirectTemplate = template.Must(template.New("redirect").Parse(`<!doctype html>
+<html lang="en">
+  <head>
+    <title>{{ .Title }}</title>
+    <meta charset="UTF-8" />
+    <meta name="robots" content="noindex" />
+    <meta http-equiv="refresh" content="0; url={{ .Url }}" />
+    <link rel="canonical" href="{{ .Url }}" />
+  </head>
+  <body>
+    <p>This page has moved to <a href="{{ .Url }}">{{ .Url }}</a>.</p>
+  </body>
+</html>
+`))
+
+// permalink returns the url of a post for the given pattern. The pattern can
+// contain the ":slug", ":year", ":month" and ":day" placeholders, which are
+// replaced with the slug and the publish date of the post.
+func permalink(pattern, slug string, publishedAt time.Time) string {
+	return strings.NewReplacer(
+		":slug", slug,
+		":year", publishedAt.Format("2006"),
+		":month", publishedAt.Format("01"),
+		":day", publishedAt.Format("02"),
+	).Replace(pattern)
+}
+
+// validateUrlPath returns an error, if the value can not be used as url of a
+// page. The url must be an absolute path, which starts and ends with a slash,
+// so that the page can be written to the "index.html" file of a directory.
+func validateUrlPath(value string) error {
+	if !strings.HasPrefix(value, "/") || !strings.HasSuffix(value, "/") {
+		return fmt.Errorf("url %q must start and end with a slash", value)
+	}
+	if value == "/" || strings.ContainsAny(value, "?#\\ ") {
+		return fmt.Errorf("url %q must be a path of a page", value)
+	}
+	for _, segment := range strings.Split(strings.Trim(value, "/"), "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return fmt.Errorf("url %q must not contain empty, \".\" or \"..\" segments", value)
+		}
+	}
+	return nil
+}
+
+// checkReservedUrl returns an error, if the url is used by a generated page,
+// e.g. a tag or archive page, or if the page of the url would be written into
+// the directory of a generated file, e.g. a feed.
+func checkReservedUrl(value string) error {
+	if slices.Contains(reservedUrls, value) || archiveUrlRegexp.MatchString(value) {
+		return fmt.Errorf("url %q is used by a generated page", value)
+	}
+	for _, prefix := range reservedUrlPrefixes {
+		if strings.HasPrefix(value, prefix) {
+			return fmt.Errorf("url %q is used by the generated pages below %q", value, prefix)
+		}
+	}
+
+	segments := strings.Split(strings.Trim(value, "/"), "/")
+	if slices.Contains(segments, "feed.xml") || slices.Contains([]string{"404.html", "sitemap.xml", redirectsFile}, segments[0]) {
+		return fmt.Errorf("url %q is used by a generated file", value)
+	}
+
+	return nil
+}
+
+// checkUrls adds an error for every url and alias of the posts, which is also
+// used by another post or by a generated page, so that no post overwrites
+// another page or its redirects.
+func (b *Builder) checkUrls(posts []BlogPost) {
+	used := make(map[string]string)
+
+	for _, post := range posts {
+		for _, url := range append([]string{post.Url}, post.Aliases...) {
+			if err := checkReservedUrl(url); err != nil {
+				b.errs.Add(fmt.Sprintf("blog/%s/%s.md", post.ID, post.ID), "url", err)
+				continue
+			}
+			if other, ok := used[url]; ok {
+				b.errs.Add(fmt.Sprintf("blog/%s/%s.md", post.ID, post.ID), "url", fmt.Errorf("url %q is also used by %q", url, other))
+				continue
+			}
+			used[url] = post.ID
+		}
+	}
+}
+
+// buildRedirects builds a redirect page for every alias of the posts and the
+// "_redirects" file with the aliases of all listed posts. The file is only
+// written, when it isn't empty. The files are built via the cache like all
+// other pages, so that the cache doesn't remove an alias page, which is
+// written to the old url of a renamed post, and removes a "_redirects" file,
+// which isn't needed anymore.
+func (b *Builder) buildRedirects(p *pool, posts []BlogPost) {
+	site := b.site
+
+	var redirects []string
+	for _, post := range posts {
+		for _, alias := range post.Aliases {
+			if !post.Unlisted {
+				redirects = append(redirects, fmt.Sprintf("%s %s 301", alias, post.Url))
+			}
+
+			data := map[string]string{
+				"Title": post.Title,
+				"Url":   fmt.Sprintf("%s%s", site.BaseUrl, post.Url),
+			}
+
+			p.Go(func() {
+				hash, err := hashOf(data)
+				if err != nil {
+					b.errs.Add(alias, "redirect", err)
+					return
+				}
+
+				b.errs.Add(alias, "redirect", b.cache.Build(fmt.Sprintf("%s/index.html", urlPath(alias)), hash, func() error {
+					var buf bytes.Buffer
+					if err := redirectTemplate.Execute(&buf, data); err != nil {
+						return err
+					}
+
+					return b.out.WriteFile(fmt.Sprintf("%s/index.html", urlPath(alias)), buf.Bytes())
+				}))
+			})
+		}
+	}
+
+	if len(redirects) == 0 {
+		return
+	}
+
+	slices.Sort(redirects)
+
+	p.Go(func() {
+		hash, err := hashOf(redirects)
+		if err != nil {
+			b.errs.Add(redirectsFile, "redirect", err)
+			return
+		}
+
+		b.errs.Add(redirectsFile, "redirect", b.cache.Build(redirectsFile, hash, func() error {
+			var buf bytes.Buffer
+			for _, redirect := range redirects {
+				fmt.Fprintln(&buf, redirect)
+			}
+			return b.out.WriteFile(redirectsFile, buf.Bytes())
+		}))
+	})
+}
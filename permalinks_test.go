@@ -0,0 +1,34 @@
+package main
+
+import "testing"
+
+func TestCheckReservedUrl(t *testing.T) {
+	for _, tt := range []struct {
+		url      string
+		reserved bool
+	}{
+		{url: "/blog/posts/my-post/", reserved: false},
+		{url: "/blog/my-post/", reserved: false},
+		{url: "/blog/2025/06/my-post/", reserved: false},
+		{url: "/blog/", reserved: true},
+		{url: "/about/", reserved: true},
+		{url: "/blog/archive/", reserved: true},
+		{url: "/blog/page/", reserved: true},
+		{url: "/blog/page/2/", reserved: true},
+		{url: "/blog/tags/", reserved: true},
+		{url: "/blog/tags/go/", reserved: true},
+		{url: "/blog/series/my-series/", reserved: true},
+		{url: "/blog/authors/jane-doe/", reserved: true},
+		{url: "/blog/2025/", reserved: true},
+		{url: "/blog/2025/06/", reserved: true},
+		{url: "/cheat-sheets/git/", reserved: true},
+		{url: "/blog/feed.xml/", reserved: true},
+		{url: "/sitemap.xml/", reserved: true},
+	} {
+		t.Run(tt.url, func(t *testing.T) {
+			if err := checkReservedUrl(tt.url); (err != nil) != tt.reserved {
+				t.Errorf("checkReservedUrl(%q) = %v, expected reserved: %t", tt.url, err, tt.reserved)
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"sync"
+)
+
+// pool runs functions concurrently, but never more than the configured number
+// of jobs at the same time. The functions must not add new functions to the
+// same pool, because this could block forever.
+type pool struct {
+	wg  sync.WaitGroup
+	sem chan struct{}
+}
+
+func newPool(jobs int) *pool {
+	if jobs < 1 {
+		jobs = 1
+	}
+
+	return &pool{
+		sem: make(chan struct{}, jobs),
+	}
+}
+
+// Go runs the function in a new goroutine, as soon as one of the jobs is free.
+func (p *pool) Go(fn func()) {
+	p.wg.Add(1)
+	p.sem <- struct{}{}
+
+	go func() {
+		defer p.wg.Done()
+		defer func() { <-p.sem }()
+
+		fn()
+	}()
+}
+
+// Wait waits until all functions of the pool are done.
+func (p *pool) Wait() {
+	p.wg.Wait()
+}
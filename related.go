@@ -0,0 +1,90 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+)
+
+// defaultRelatedPosts is the number of related posts, when it isn't set in the
+// site configuration.
+const defaultRelatedPosts = 3
+
+// relatePosts sets the related posts of every post. The posts from the
+// "Related" field of the front matter are always added first. The remaining
+// places are filled with the posts, which share the most tags with the post.
+// Every shared tag is weighted by the inverse of its number of posts, so that
+// rare tags count more than tags which are used by almost every post. Posts
+// with the same score are sorted by their publish date, starting with the
+// newest one.
+//
+// The "all" posts are used to validate the "Related" field, so that posts,
+// which are linked but not built, e.g. drafts, are skipped without an error.
+func (b *Builder) relatePosts(posts []BlogPost, all []BlogPost) {
+	tagCounts := make(map[string]int)
+	for _, post := range posts {
+		for _, tag := range post.Tags {
+			tagCounts[tag.Slug]++
+		}
+	}
+
+	type scoredPost struct {
+		post  BlogPost
+		score float64
+	}
+
+	for i, post := range posts {
+		var related []BlogPostLink
+
+		for _, id := range post.RelatedIDs {
+			if id == post.ID {
+				b.errs.Add(fmt.Sprintf("blog/%s/%s.md", post.ID, post.ID), "related", fmt.Errorf("post can not be related to itself"))
+				continue
+			}
+			if !slices.ContainsFunc(all, func(p BlogPost) bool { return p.ID == id }) {
+				b.errs.Add(fmt.Sprintf("blog/%s/%s.md", post.ID, post.ID), "related", fmt.Errorf("unknown related post %q", id))
+				continue
+			}
+
+			if index := slices.IndexFunc(posts, func(p BlogPost) bool { return p.ID == id }); index != -1 {
+				related = append(related, newBlogPostLink(posts[index]))
+			}
+		}
+
+		var candidates []scoredPost
+		for _, other := range posts {
+			if other.ID == post.ID || slices.Contains(post.RelatedIDs, other.ID) {
+				continue
+			}
+
+			// The tags are always summed up in the order of the post's tags, so
+			// that posts with the same shared tags get exactly the same score.
+			var score float64
+			for _, tag := range post.Tags {
+				if slices.ContainsFunc(other.Tags, func(t BlogPostTag) bool { return t.Slug == tag.Slug }) {
+					score += 1 / float64(tagCounts[tag.Slug])
+				}
+			}
+
+			if score > 0 {
+				candidates = append(candidates, scoredPost{post: other, score: score})
+			}
+		}
+
+		slices.SortStableFunc(candidates, func(a, b scoredPost) int {
+			if c := cmp.Compare(b.score, a.score); c != 0 {
+				return c
+			}
+			return b.post.PublishedAt.Compare(a.post.PublishedAt)
+		})
+
+		for _, candidate := range candidates {
+			if len(related) >= b.site.RelatedPosts {
+				break
+			}
+			related = append(related, newBlogPostLink(candidate.post))
+		}
+
+		posts[i].Related = related
+	}
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testPost returns a post with the given id, publish date and tag slugs.
+func testPost(id, publishedAt string, tags ...string) BlogPost {
+	date, err := time.Parse(time.DateOnly, publishedAt)
+	if err != nil {
+		panic(err)
+	}
+
+	post := BlogPost{ID: id, PublishedAt: date}
+	for _, tag := range tags {
+		post.Tags = append(post.Tags, BlogPostTag{Slug: tag, Name: tag})
+	}
+	return post
+}
+
+func linkIDs(links []BlogPostLink) []string {
+	ids := make([]string, 0, len(links))
+	for _, link := range links {
+		ids = append(ids, link.ID)
+	}
+	return ids
+}
+
+func TestRelatePosts(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		related map[string][]string
+		draft   bool
+		want    map[string][]string
+		errs    []string
+	}{
+		{
+			name: "rare tags count more",
+			want: map[string][]string{
+				// p3 shares the rare tag, p2 and p4 only the common tag and
+				// the newer post wins the tie.
+				"p1": {"p3", "p2"},
+				"p2": {"p1", "p3"},
+				"p5": {},
+			},
+		},
+		{
+			name:    "pinned posts first",
+			related: map[string][]string{"p1": {"p5"}, "p5": {"p4", "p2"}},
+			want: map[string][]string{
+				"p1": {"p5", "p3"},
+				"p5": {"p4", "p2"},
+			},
+		},
+		{
+			name:    "drafts are skipped",
+			related: map[string][]string{"p1": {"draft"}},
+			draft:   true,
+			want: map[string][]string{
+				"p1": {"p3", "p2"},
+			},
+		},
+		{
+			name:    "invalid related posts",
+			related: map[string][]string{"p1": {"p1", "unknown"}},
+			want: map[string][]string{
+				"p1": {"p3", "p2"},
+			},
+			errs: []string{"post can not be related to itself", `unknown related post "unknown"`},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			posts := []BlogPost{
+				testPost("p1", "2025-05-01", "go", "rare"),
+				testPost("p2", "2025-04-01", "go"),
+				testPost("p3", "2025-03-01", "go", "rare"),
+				testPost("p4", "2025-02-01", "go"),
+				testPost("p5", "2025-01-01", "kubernetes"),
+			}
+			for i := range posts {
+				posts[i].RelatedIDs = tt.related[posts[i].ID]
+			}
+
+			all := slices.Clone(posts)
+			if tt.draft {
+				all = append(all, testPost("draft", "2025-06-01", "go", "rare"))
+			}
+
+			b := newSiteBuilder(Site{RelatedPosts: 2}, nil, newMemOutput(), BuildOptions{})
+			b.relatePosts(posts, all)
+
+			for _, post := range posts {
+				want, ok := tt.want[post.ID]
+				if !ok {
+					continue
+				}
+				if ids := linkIDs(post.Related); !slices.Equal(ids, want) {
+					t.Errorf("post %s: expected related posts %v, got %v", post.ID, want, ids)
+				}
+			}
+
+			err := b.errs.Err()
+			if len(tt.errs) == 0 && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			for _, want := range tt.errs {
+				if err == nil || !strings.Contains(err.Error(), want) {
+					t.Errorf("expected error %q, got %v", want, err)
+				}
+			}
+		})
+	}
+}
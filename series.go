@@ -0,0 +1,126 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+)
+
+// BlogPostLink is a link to a blog post, which is used when a page references
+// other posts, so that the content of the referenced posts isn't copied into
+// every page.
+type BlogPostLink struct {
+	ID          string
+	Title       string
+	Description string
+	Url         string
+	PublishedAt time.Time
+}
+
+func newBlogPostLink(post BlogPost) BlogPostLink {
+	return BlogPostLink{
+		ID:          post.ID,
+		Title:       post.Title,
+		Description: post.Description,
+		Url:         post.Url,
+		PublishedAt: post.PublishedAt,
+	}
+}
+
+// BlogSeries is a series of blog posts. The posts are sorted by their
+// "SeriesOrder" field.
+type BlogSeries struct {
+	Name  string
+	Slug  string
+	Url   string
+	Posts []BlogPostLink
+}
+
+// newestPost returns the publish date of the newest post of the series.
+func (s *BlogSeries) newestPost() time.Time {
+	var newest time.Time
+	for _, post := range s.Posts {
+		if post.PublishedAt.After(newest) {
+			newest = post.PublishedAt
+		}
+	}
+	return newest
+}
+
+// collectSeries groups the posts by their series and sets the series of every
+// post, which is part of a series. Posts with the same "SeriesOrder" in one
+// series are added to the build errors. The series are returned sorted by
+// their slug.
+func (b *Builder) collectSeries(posts []BlogPost) []*BlogSeries {
+	seriesPosts := make(map[string][]BlogPost)
+	for _, post := range posts {
+		if post.SeriesName != "" {
+			slug := slugify(post.SeriesName)
+			seriesPosts[slug] = append(seriesPosts[slug], post)
+		}
+	}
+
+	var series []*BlogSeries
+	seriesBySlug := make(map[string]*BlogSeries)
+
+	for _, slug := range slices.Sorted(maps.Keys(seriesPosts)) {
+		parts := slices.Clone(seriesPosts[slug])
+		slices.SortStableFunc(parts, func(a, b BlogPost) int {
+			return a.SeriesOrder - b.SeriesOrder
+		})
+
+		s := &BlogSeries{
+			Name: parts[0].SeriesName,
+			Slug: slug,
+			Url:  fmt.Sprintf("/blog/series/%s/", slug),
+		}
+		for i, part := range parts {
+			if i > 0 && parts[i-1].SeriesOrder == part.SeriesOrder {
+				b.errs.Add(fmt.Sprintf("blog/%s/%s.md", part.ID, part.ID), "series", fmt.Errorf("SeriesOrder %d of series %q is also used by %q", part.SeriesOrder, s.Name, parts[i-1].ID))
+			}
+			s.Posts = append(s.Posts, newBlogPostLink(part))
+		}
+
+		series = append(series, s)
+		seriesBySlug[slug] = s
+	}
+
+	for i := range posts {
+		if posts[i].SeriesName != "" {
+			posts[i].Series = seriesBySlug[slugify(posts[i].SeriesName)]
+		}
+	}
+
+	return series
+}
+
+// buildSeries builds the landing page and the feed for every series with the
+// given pool. The feed contains the posts of the series sorted by their
+// publish date like all other feeds.
+func (b *Builder) buildSeries(p *pool, series []*BlogSeries, posts []BlogPost) {
+	site := b.site
+
+	for _, s := range series {
+		var seriesPosts []BlogPost
+		for _, post := range posts {
+			if post.Series != nil && post.Series.Slug == s.Slug {
+				seriesPosts = append(seriesPosts, post)
+			}
+		}
+
+		metadata := site.metadata(site.Sections.Blog, s.Url)
+		metadata.Title = site.title(s.Name, site.Sections.Blog.ItemTitle)
+
+		p.Go(func() {
+			b.errs.Add("templates/blog-series.html", fmt.Sprintf("render %s", metadata.Url), b.buildTemplate("blog-series", urlPath(metadata.Url), Data{
+				Metadata: metadata,
+				Content:  s,
+			}))
+		})
+
+		p.Go(func() {
+			b.errs.Add("blog", fmt.Sprintf("feed %sfeed.xml", metadata.Url), b.buildRssFeed(urlPath(metadata.Url), metadata, seriesPosts))
+		})
+	}
+}
@@ -0,0 +1,272 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"maps"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// liveReloadScript is injected into all HTML pages, when the server runs in
+// watch mode. It reloads the page after each successful build and shows the
+// errors of a failed build in an overlay.
+const liveReloadScript = `<script>
+  (() => {
+    const source = new EventSource("/_livereload");
+    source.addEventListener("reload", () => location.reload());
+    source.addEventListener("build-error", (event) => {
+      let overlay = document.getElementById("livereload-error");
+      if (!overlay) {
+        overlay = document.createElement("pre");
+        overlay.id = "livereload-error";
+        overlay.style.cssText =
+          "position:fixed;inset:0;z-index:9999;margin:0;padding:24px;overflow:auto;white-space:pre-wrap;font-size:14px;background:rgba(36,39,58,0.95);color:#ed8796;";
+        document.body.appendChild(overlay);
+      }
+      overlay.textContent = JSON.parse(event.data);
+    });
+  })();
+</script>
+`
+
+// watchInterval is the interval in which the source files are checked for
+// changes in watch mode.
+const watchInterval = 500 * time.Millisecond
+
+// runServer starts a local server to preview the site from the "dist"
+// directory. In watch mode the site is built first and rebuilt on every change
+// of the source files. The open browsers are reloaded via Server-Sent Events
+// after each build.
+func runServer(addr, dist string, watch bool, opts BuildOptions) error {
+	mux := http.NewServeMux()
+	var handler http.Handler = http.FileServer(http.Dir(dist))
+
+	if watch {
+		reload := &liveReload{
+			clients: make(map[chan liveReloadEvent]struct{}),
+		}
+
+		reload.build(opts)
+		opts.Force = false
+
+		config := opts.Config
+		if !filepath.IsAbs(config) {
+			config = filepath.Join(opts.Src, config)
+		}
+
+		go reload.watch(opts, []string{
+			filepath.Join(opts.Src, "blog"),
+			filepath.Join(opts.Src, "cheat-sheets"),
+			filepath.Join(opts.Src, "templates"),
+			config,
+		})
+
+		mux.Handle("/_livereload", reload)
+		handler = injectLiveReload(dist, handler)
+	}
+
+	mux.Handle("/", handler)
+
+	slog.Info(fmt.Sprintf("Start server on %s...", addr))
+	server := &http.Server{
+		Addr:         addr,
+		Handler:      mux,
+		ReadTimeout:  10 * time.Second,
+		WriteTimeout: 10 * time.Second,
+	}
+	return server.ListenAndServe()
+}
+
+// injectLiveReload returns a handler which injects the live reload script into
+// all HTML pages. All other files are served by the "next" handler.
+func injectLiveReload(dist string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := path.Clean(r.URL.Path)
+		if strings.HasSuffix(r.URL.Path, "/") {
+			name = path.Join(name, "index.html")
+		}
+
+		if path.Ext(name) != ".html" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		content, err := fs.ReadFile(os.DirFS(dist), strings.TrimPrefix(name, "/"))
+		if err != nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		content = bytes.Replace(content, []byte("</body>"), []byte(liveReloadScript+"</body>"), 1)
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Header().Set("Cache-Control", "no-cache")
+		_, _ = w.Write(content)
+	})
+}
+
+type liveReloadEvent struct {
+	name string
+	data string
+}
+
+// liveReload rebuilds the site and sends the result of each build to all
+// connected browsers. The error of the last build is kept, so that it is also
+// shown in browsers which connect after the build.
+type liveReload struct {
+	mu      sync.Mutex
+	clients map[chan liveReloadEvent]struct{}
+	lastErr string
+}
+
+func (l *liveReload) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// The connection is kept open until the browser closes it, so that we have
+	// to disable the write timeout of the server for this handler.
+	rc := http.NewResponseController(w)
+	_ = rc.SetWriteDeadline(time.Time{})
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+
+	events := make(chan liveReloadEvent, 1)
+
+	l.mu.Lock()
+	l.clients[events] = struct{}{}
+	lastErr := l.lastErr
+	l.mu.Unlock()
+
+	defer func() {
+		l.mu.Lock()
+		delete(l.clients, events)
+		l.mu.Unlock()
+	}()
+
+	if lastErr != "" {
+		writeEvent(w, liveReloadEvent{name: "build-error", data: lastErr})
+	}
+	_ = rc.Flush()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case event := <-events:
+			writeEvent(w, event)
+			if err := rc.Flush(); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// writeEvent writes the event in the Server-Sent Events format. The data is
+// encoded as JSON, so that it doesn't contain any newlines.
+func writeEvent(w http.ResponseWriter, event liveReloadEvent) {
+	data, _ := json.Marshal(event.data)
+	fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.name, data)
+}
+
+func (l *liveReload) broadcast(event liveReloadEvent) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for client := range l.clients {
+		select {
+		case client <- event:
+		default:
+		}
+	}
+}
+
+// build builds the site and notifies all browsers about the result.
+func (l *liveReload) build(opts BuildOptions) {
+	err := runBuild(opts)
+
+	l.mu.Lock()
+	l.lastErr = ""
+	if err != nil {
+		l.lastErr = formatBuildError(err)
+	}
+	lastErr := l.lastErr
+	l.mu.Unlock()
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "\n%s\n", lastErr)
+		l.broadcast(liveReloadEvent{name: "build-error", data: lastErr})
+		return
+	}
+
+	l.broadcast(liveReloadEvent{name: "reload"})
+}
+
+// watch checks the given paths for changes and rebuilds the site, when a file
+// was added, changed or removed. We poll the files instead of using file
+// system notifications, so that we do not need an additional dependency.
+func (l *liveReload) watch(opts BuildOptions, paths []string) {
+	last, err := snapshotFiles(paths)
+	if err != nil {
+		slog.Error("Failed to watch files", slog.Any("error", err))
+	}
+
+	ticker := time.NewTicker(watchInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		current, err := snapshotFiles(paths)
+		if err != nil {
+			slog.Error("Failed to watch files", slog.Any("error", err))
+			continue
+		}
+
+		if maps.Equal(last, current) {
+			continue
+		}
+		last = current
+
+		slog.Info("Files changed, rebuild site...")
+		l.build(opts)
+	}
+}
+
+// snapshotFiles returns the modification time and size of all files in the
+// given paths. Paths which do not exist are ignored.
+func snapshotFiles(paths []string) (map[string]string, error) {
+	snapshot := make(map[string]string)
+
+	for _, root := range paths {
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				if errors.Is(err, fs.ErrNotExist) {
+					return nil
+				}
+				return err
+			}
+			if d.IsDir() {
+				return nil
+			}
+
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+
+			snapshot[path] = fmt.Sprintf("%d-%d", info.ModTime().UnixNano(), info.Size())
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return snapshot, nil
+}
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-yaml"
+)
+
+// Site is the site configuration, which is loaded once from the "site.yaml"
+// file and passed to all builders. It contains all values which were
+// previously hardcoded, so that the generator can be used for other sites.
+//
+// If "UpdatedFromGit" is set, the time of the last commit, which changed the
+// content of a post, is used for posts without an "UpdatedAt" field. Commits,
+// which only change the front matter of a post, are ignored.
+type Site struct {
+	Title          string         `yaml:"title"`
+	Author         string         `yaml:"author"`
+	AuthorTitle    string         `yaml:"authorTitle"`
+	AuthorImage    string         `yaml:"authorImage"`
+	Tagline        string         `yaml:"tagline"`
+	Keywords       []string       `yaml:"keywords"`
+	BaseUrl        string         `yaml:"baseUrl"`
+	Permalink      string         `yaml:"permalink"`
+	Image          string         `yaml:"image"`
+	Twitter        string         `yaml:"twitter"`
+	Timezone       string         `yaml:"timezone"`
+	Feed           SiteFeed       `yaml:"feed"`
+	Pagination     SitePagination `yaml:"pagination"`
+	RelatedPosts   int            `yaml:"relatedPosts"`
+	TocDepth       int            `yaml:"tocDepth"`
+	UpdatedFromGit bool           `yaml:"updatedFromGit"`
+	Sections       SiteSections   `yaml:"sections"`
+
+	location *time.Location
+}
+
+// SiteFeed is the configuration of the feeds. The content is "full" or
+// "excerpt" and defines if the feeds contain the full posts or only the part
+// before the "<!--more-->" marker.
+type SiteFeed struct {
+	Language  string       `yaml:"language"`
+	Copyright string       `yaml:"copyright"`
+	Content   string       `yaml:"content"`
+	Icon      SiteFeedIcon `yaml:"icon"`
+}
+
+type SiteFeedIcon struct {
+	Url    string `yaml:"url"`
+	Width  int    `yaml:"width"`
+	Height int    `yaml:"height"`
+}
+
+// SitePagination is the number of posts per page for the blog index, the tag
+// pages and the author pages. If the number is zero, all posts are shown on a
+// single page.
+type SitePagination struct {
+	Blog    int `yaml:"blog"`
+	Tags    int `yaml:"tags"`
+	Authors int `yaml:"authors"`
+}
+
+type SiteSections struct {
+	Home        SiteSection `yaml:"home"`
+	About       SiteSection `yaml:"about"`
+	Analytics   SiteSection `yaml:"analytics"`
+	NotFound    SiteSection `yaml:"notFound"`
+	Blog        SiteSection `yaml:"blog"`
+	CheatSheets SiteSection `yaml:"cheatSheets"`
+}
+
+// SiteSection is the metadata for a section of the site. The "ItemTitle" is
+// used in the title of the single pages of a section, e.g. "Cheat Sheet" for
+// a single cheat sheet. All empty fields are set to the site defaults when the
+// configuration is loaded.
+type SiteSection struct {
+	Title       string   `yaml:"title"`
+	ItemTitle   string   `yaml:"itemTitle"`
+	Description string   `yaml:"description"`
+	Keywords    []string `yaml:"keywords"`
+	Image       string   `yaml:"image"`
+}
+
+// loadSite reads the site configuration from the named file in the file system
+// and sets the defaults for all sections.
+func loadSite(fsys fs.FS, name string) (Site, error) {
+	content, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return Site{}, err
+	}
+
+	var site Site
+	if err := yaml.Unmarshal(content, &site); err != nil {
+		return Site{}, err
+	}
+
+	site.BaseUrl = strings.TrimSuffix(site.BaseUrl, "/")
+
+	// The timezone is used to parse dates without timezone information, e.g.
+	// the "PublishedAt" field of blog posts. If it isn't set, we use UTC.
+	site.location, err = time.LoadLocation(site.Timezone)
+	if err != nil {
+		return Site{}, err
+	}
+
+	// The permalink is the url of all blog posts, which do not set their own
+	// url via the "Permalink" field.
+	if site.Permalink == "" {
+		site.Permalink = defaultPermalink
+	}
+	if !strings.Contains(site.Permalink, ":slug") {
+		return Site{}, fmt.Errorf("invalid permalink %q: must contain :slug", site.Permalink)
+	}
+	if err := validateUrlPath(permalink(site.Permalink, "slug", time.Time{})); err != nil {
+		return Site{}, fmt.Errorf("invalid permalink %q: %w", site.Permalink, err)
+	}
+	if err := checkReservedUrl(permalink(site.Permalink, "slug", time.Time{})); err != nil {
+		return Site{}, fmt.Errorf("invalid permalink %q: %w", site.Permalink, err)
+	}
+
+	if site.RelatedPosts == 0 {
+		site.RelatedPosts = defaultRelatedPosts
+	}
+
+	if site.TocDepth == 0 {
+		site.TocDepth = defaultTocDepth
+	}
+
+	if site.Feed.Copyright == "" {
+		site.Feed.Copyright = site.Author
+	}
+
+	switch site.Feed.Content {
+	case "":
+		site.Feed.Content = feedContentFull
+	case feedContentFull, feedContentExcerpt:
+	default:
+		return Site{}, fmt.Errorf("invalid feed content %q: must be %q or %q", site.Feed.Content, feedContentFull, feedContentExcerpt)
+	}
+
+	for _, section := range []*SiteSection{
+		&site.Sections.Home,
+		&site.Sections.About,
+		&site.Sections.Analytics,
+		&site.Sections.NotFound,
+		&site.Sections.Blog,
+		&site.Sections.CheatSheets,
+	} {
+		if section.ItemTitle == "" {
+			section.ItemTitle = section.Title
+		}
+		if section.Description == "" {
+			section.Description = site.Tagline
+		}
+		if section.Keywords == nil {
+			section.Keywords = site.Keywords
+		}
+		if section.Image == "" {
+			section.Image = site.Image
+		}
+	}
+
+	return site, nil
+}
+
+// title returns the page title for the given parts, e.g. "Home - Rico Berger"
+// for the "Home" part.
+func (s Site) title(parts ...string) string {
+	return strings.Join(append(parts, s.Title), " - ")
+}
+
+// metadata returns the metadata for the index page of the given section.
+func (s Site) metadata(section SiteSection, url string) Metadata {
+	return Metadata{
+		Title:       s.title(section.Title),
+		Description: section.Description,
+		Author:      s.Author,
+		Keywords:    section.Keywords,
+		BaseUrl:     s.BaseUrl,
+		Url:         url,
+		Image:       section.Image,
+		Prism:       false,
+	}
+}
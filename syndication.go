@@ -0,0 +1,39 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// BlogPostSyndication is a copy of a blog post on another site, e.g. on dev.to
+// or Medium. The name is the host of the url, which is shown to the reader.
+type BlogPostSyndication struct {
+	Name string
+	Url  string
+}
+
+func newBlogPostSyndication(value string) BlogPostSyndication {
+	var name string
+	if u, err := url.Parse(value); err == nil {
+		name = strings.TrimPrefix(u.Hostname(), "www.")
+	}
+
+	return BlogPostSyndication{
+		Name: name,
+		Url:  value,
+	}
+}
+
+// validateAbsoluteUrl returns an error, if the value isn't an absolute http or
+// https url, which can be used to link to another site.
+func validateAbsoluteUrl(value string) error {
+	u, err := url.Parse(value)
+	if err != nil {
+		return fmt.Errorf("url %q is invalid: %w", value, err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("url %q must be an absolute http or https url", value)
+	}
+	return nil
+}
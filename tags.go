@@ -0,0 +1,255 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"math"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/goccy/go-yaml"
+)
+
+// tagsFile is the configuration of the tags. It is optional, tags which are
+// not configured are shown with the name from the front matter of the posts.
+const tagsFile = "blog/tags.yaml"
+
+// tagCloudClasses are the classes of the tags in the tag cloud for every
+// weight, starting with the weight 1. The tag with the fewest posts has the
+// weight 1, the tag with the most posts has the weight "tagCloudWeights".
+var tagCloudClasses = []string{"text-sm", "text-base", "text-lg", "text-xl", "text-2xl"}
+
+// tagCloudWeights is the number of different weights for the tags in the tag
+// cloud.
+var tagCloudWeights = len(tagCloudClasses)
+
+// TagConfig is the configuration of a tag in the "blog/tags.yaml" file, where
+// the tags are listed by their slug. The aliases are other tags, which are
+// replaced by this tag, e.g. "k8s" for "kubernetes". If the name isn't set, the
+// name from the front matter of the posts is used.
+type TagConfig struct {
+	Name        string   `yaml:"name"`
+	Aliases     []string `yaml:"aliases"`
+	Description string   `yaml:"description"`
+	Image       string   `yaml:"image"`
+}
+
+// BlogPostTag is a tag of a blog post. The slug is used in the urls of the tag
+// pages, the name is shown to the reader.
+type BlogPostTag struct {
+	Slug string
+	Name string
+}
+
+// slugWords are the characters, which are replaced by a word in a slug, so
+// that e.g. "C", "C++" and "C#" get different slugs.
+var slugWords = map[rune]string{
+	'+': "plus",
+	'#': "sharp",
+}
+
+// slugify returns the slug for the given tag, e.g. "continuous-profiling" for
+// "Continuous Profiling" or "c-plus-plus" for "C++". All other characters,
+// which are not a letter or digit, are replaced by a dash.
+func slugify(tag string) string {
+	var slug strings.Builder
+	dash := false
+
+	for _, r := range strings.ToLower(tag) {
+		if word, ok := slugWords[r]; ok {
+			if slug.Len() > 0 {
+				slug.WriteByte('-')
+			}
+			slug.WriteString(word)
+			dash = true
+		} else if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if dash && slug.Len() > 0 {
+				slug.WriteByte('-')
+			}
+			slug.WriteRune(r)
+			dash = false
+		} else {
+			dash = true
+		}
+	}
+
+	return slug.String()
+}
+
+// tagNames returns the names of the given tags.
+func tagNames(tags []BlogPostTag) []string {
+	names := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		names = append(names, tag.Name)
+	}
+	return names
+}
+
+// loadTags loads the configuration of the tags from the "blog/tags.yaml" file.
+// If the file doesn't exist, all tags are used as they are.
+func (b *Builder) loadTags() {
+	b.tagConfigs = make(map[string]TagConfig)
+	b.tagAliases = make(map[string]string)
+
+	content, err := fs.ReadFile(b.src, tagsFile)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			b.errs.Add(tagsFile, "read", err)
+		}
+		return
+	}
+
+	var configs map[string]TagConfig
+	if err := yaml.UnmarshalWithOptions(content, &configs, yaml.DisallowUnknownField()); err != nil {
+		b.errs.Add(tagsFile, "yaml", errors.New(yaml.FormatError(err, false, false)))
+		return
+	}
+
+	for _, slug := range slices.Sorted(maps.Keys(configs)) {
+		if slugify(slug) != slug {
+			b.errs.Add(tagsFile, "tags", fmt.Errorf("tag %q must be a slug, e.g. %q", slug, slugify(slug)))
+			continue
+		}
+
+		for _, alias := range configs[slug].Aliases {
+			aliasSlug := slugify(alias)
+			if _, ok := configs[aliasSlug]; ok {
+				b.errs.Add(tagsFile, "tags", fmt.Errorf("alias %q of tag %q is also a tag", alias, slug))
+				continue
+			}
+			if other, ok := b.tagAliases[aliasSlug]; ok {
+				b.errs.Add(tagsFile, "tags", fmt.Errorf("alias %q of tag %q is already an alias of tag %q", alias, slug, other))
+				continue
+			}
+
+			b.tagAliases[aliasSlug] = slug
+		}
+
+		b.tagConfigs[slug] = configs[slug]
+	}
+}
+
+// resolveTags returns the tags of a post for the tags from its front matter.
+// Aliases are replaced by their tag and tags with the same slug are only
+// returned once.
+func (b *Builder) resolveTags(rawTags []string) ([]BlogPostTag, error) {
+	var tags []BlogPostTag
+
+	for _, rawTag := range rawTags {
+		tag := BlogPostTag{Slug: slugify(rawTag), Name: rawTag}
+		if tag.Slug == "" {
+			return nil, fmt.Errorf("invalid tag %q: must contain at least one letter or digit", rawTag)
+		}
+
+		if slug, ok := b.tagAliases[tag.Slug]; ok {
+			tag = BlogPostTag{Slug: slug, Name: slug}
+		}
+		if name := b.tagConfigs[tag.Slug].Name; name != "" {
+			tag.Name = name
+		}
+
+		if !slices.ContainsFunc(tags, func(t BlogPostTag) bool { return t.Slug == tag.Slug }) {
+			tags = append(tags, tag)
+		}
+	}
+
+	return tags, nil
+}
+
+// nameTags sets the same name for all tags with the same slug, so that e.g.
+// "Kubernetes" and "kubernetes" are shown with the same name. If the name
+// isn't configured, the name which is used by most posts is used.
+func (b *Builder) nameTags(posts []BlogPost) {
+	counts := make(map[string]map[string]int)
+	for _, post := range posts {
+		for _, tag := range post.Tags {
+			if counts[tag.Slug] == nil {
+				counts[tag.Slug] = make(map[string]int)
+			}
+			counts[tag.Slug][tag.Name]++
+		}
+	}
+
+	b.tagNames = make(map[string]string)
+	for slug, names := range counts {
+		for _, name := range slices.Sorted(maps.Keys(names)) {
+			if names[name] > names[b.tagNames[slug]] {
+				b.tagNames[slug] = name
+			}
+		}
+	}
+
+	for _, post := range posts {
+		for i, tag := range post.Tags {
+			post.Tags[i].Name = b.tagNames[tag.Slug]
+		}
+	}
+}
+
+// BlogTagSummary is a single tag on the "/blog/tags/" page.
+type BlogTagSummary struct {
+	Tag        string
+	Url        string
+	FeedUrl    string
+	Count      int
+	LatestPost time.Time
+	Weight     int
+	Class      string
+}
+
+// tagSummaries returns the summaries for all tags sorted by their slug. The
+// posts of every tag must be sorted by their publish date.
+func (b *Builder) tagSummaries(tags map[string][]BlogPost) []BlogTagSummary {
+	minCount, maxCount := math.MaxInt, 0
+	for _, posts := range tags {
+		minCount = min(minCount, len(posts))
+		maxCount = max(maxCount, len(posts))
+	}
+
+	var summaries []BlogTagSummary
+	for _, tag := range slices.Sorted(maps.Keys(tags)) {
+		url := fmt.Sprintf("/blog/tags/%s/", tag)
+		weight := tagWeight(len(tags[tag]), minCount, maxCount)
+
+		summaries = append(summaries, BlogTagSummary{
+			Tag:        b.tagNames[tag],
+			Url:        url,
+			FeedUrl:    fmt.Sprintf("%sfeed.xml", url),
+			Count:      len(tags[tag]),
+			LatestPost: newestPost(tags[tag]),
+			Weight:     weight,
+			Class:      tagCloudClasses[weight-1],
+		})
+	}
+
+	return summaries
+}
+
+// tagWeight returns the weight of a tag with the given number of posts. The
+// weights are distributed logarithmically, so that a few tags with many posts
+// do not put all other tags into the lowest weight.
+func tagWeight(count, minCount, maxCount int) int {
+	if maxCount <= minCount {
+		return 1
+	}
+
+	scale := (math.Log(float64(count)) - math.Log(float64(minCount))) / (math.Log(float64(maxCount)) - math.Log(float64(minCount)))
+	return 1 + int(math.Round(scale*float64(tagCloudWeights-1)))
+}
+
+// buildTagIndex builds the "/blog/tags/" page with all tags.
+func (b *Builder) buildTagIndex(tags map[string][]BlogPost) error {
+	site := b.site
+
+	metadata := site.metadata(site.Sections.Blog, "/blog/tags/")
+	metadata.Title = site.title("Tags", site.Sections.Blog.ItemTitle)
+
+	return b.buildTemplate("blog-tags", "blog/tags", Data{
+		Metadata: metadata,
+		Content:  b.tagSummaries(tags),
+	})
+}
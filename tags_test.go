@@ -0,0 +1,147 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestSlugify(t *testing.T) {
+	for _, tt := range []struct {
+		value string
+		slug  string
+	}{
+		{value: "Kubernetes", slug: "kubernetes"},
+		{value: "Continuous Profiling", slug: "continuous-profiling"},
+		{value: "continuous_profiling", slug: "continuous-profiling"},
+		{value: "  Go -- Modules  ", slug: "go-modules"},
+		{value: "Node.js", slug: "node-js"},
+		{value: "C", slug: "c"},
+		{value: "C++", slug: "c-plus-plus"},
+		{value: "C#", slug: "c-sharp"},
+		{value: "a + b", slug: "a-plus-b"},
+		{value: "Ünïcode", slug: "ünïcode"},
+		{value: "!?", slug: ""},
+	} {
+		t.Run(tt.value, func(t *testing.T) {
+			if slug := slugify(tt.value); slug != tt.slug {
+				t.Errorf("slugify(%q) = %q, expected %q", tt.value, slug, tt.slug)
+			}
+		})
+	}
+}
+
+func TestResolveTags(t *testing.T) {
+	b := newSiteBuilder(Site{}, fstest.MapFS{
+		tagsFile: {Data: []byte(`kubernetes:
+  name: Kubernetes
+  aliases:
+    - k8s
+opentelemetry:
+  aliases:
+    - OTel
+`)},
+	}, newMemOutput(), BuildOptions{})
+	b.loadTags()
+	if err := b.errs.Err(); err != nil {
+		t.Fatalf("failed to load tags: %v", err)
+	}
+
+	for _, tt := range []struct {
+		name    string
+		rawTags []string
+		tags    []BlogPostTag
+		err     bool
+	}{
+		{
+			name:    "configured name",
+			rawTags: []string{"kubernetes"},
+			tags:    []BlogPostTag{{Slug: "kubernetes", Name: "Kubernetes"}},
+		},
+		{
+			name:    "alias",
+			rawTags: []string{"K8s"},
+			tags:    []BlogPostTag{{Slug: "kubernetes", Name: "Kubernetes"}},
+		},
+		{
+			name:    "alias without configured name",
+			rawTags: []string{"otel"},
+			tags:    []BlogPostTag{{Slug: "opentelemetry", Name: "opentelemetry"}},
+		},
+		{
+			name:    "unconfigured tag",
+			rawTags: []string{"Go Modules"},
+			tags:    []BlogPostTag{{Slug: "go-modules", Name: "Go Modules"}},
+		},
+		{
+			name:    "duplicates are removed",
+			rawTags: []string{"k8s", "Kubernetes", "go", "Go"},
+			tags:    []BlogPostTag{{Slug: "kubernetes", Name: "Kubernetes"}, {Slug: "go", Name: "go"}},
+		},
+		{
+			name:    "invalid tag",
+			rawTags: []string{"go", "!!"},
+			err:     true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			tags, err := b.resolveTags(tt.rawTags)
+			if (err != nil) != tt.err {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !slices.Equal(tags, tt.tags) {
+				t.Errorf("expected %v, got %v", tt.tags, tags)
+			}
+		})
+	}
+}
+
+func TestLoadTagsErrors(t *testing.T) {
+	b := newSiteBuilder(Site{}, fstest.MapFS{
+		tagsFile: {Data: []byte(`kubernetes:
+  aliases:
+    - k8s
+    - docker
+docker:
+  aliases:
+    - K8s
+Go:
+  name: Go
+`)},
+	}, newMemOutput(), BuildOptions{})
+	b.loadTags()
+
+	err := b.errs.Err()
+	for _, want := range []string{
+		`alias "k8s" of tag "kubernetes" is already an alias of tag "docker"`,
+		`alias "docker" of tag "kubernetes" is also a tag`,
+		`tag "Go" must be a slug`,
+	} {
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("expected error %q, got %v", want, err)
+		}
+	}
+}
+
+func TestNameTags(t *testing.T) {
+	b := newSiteBuilder(Site{}, fstest.MapFS{}, newMemOutput(), BuildOptions{})
+
+	posts := []BlogPost{
+		{ID: "a", Tags: []BlogPostTag{{Slug: "kubernetes", Name: "kubernetes"}}},
+		{ID: "b", Tags: []BlogPostTag{{Slug: "kubernetes", Name: "Kubernetes"}}},
+		{ID: "c", Tags: []BlogPostTag{{Slug: "kubernetes", Name: "Kubernetes"}, {Slug: "go", Name: "go"}}},
+		{ID: "d", Tags: []BlogPostTag{{Slug: "go", Name: "Go"}}},
+	}
+	b.nameTags(posts)
+
+	// The most used name wins, a tie is broken by the sorted names.
+	for _, post := range posts {
+		for _, tag := range post.Tags {
+			expected := map[string]string{"kubernetes": "Kubernetes", "go": "Go"}[tag.Slug]
+			if tag.Name != expected {
+				t.Errorf("post %s: expected name %q for tag %q, got %q", post.ID, expected, tag.Slug, tag.Name)
+			}
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/yuin/goldmark/ast"
+)
+
+// wordsPerMinute is the reading speed, which is used to estimate the reading
+// time of a post.
+const wordsPerMinute = 200
+
+// defaultTocDepth is the deepest heading level, which is added to the table of
+// contents, when it isn't set in the site configuration.
+const defaultTocDepth = 3
+
+// TocEntry is a heading in the table of contents of a post. The children are
+// the headings with a higher level, which follow the heading.
+type TocEntry struct {
+	Title    string
+	ID       string
+	Level    int
+	Children []TocEntry
+}
+
+// countWords returns the number of words in the markdown document. Code blocks,
+// HTML blocks and the alt texts of images are not counted, because they are
+// not read like the text.
+func countWords(doc ast.Node, source []byte) int {
+	var buf bytes.Buffer
+
+	_ = ast.Walk(doc, func(node ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			if node.Type() == ast.TypeBlock {
+				buf.WriteByte(' ')
+			}
+			return ast.WalkContinue, nil
+		}
+
+		switch n := node.(type) {
+		case *ast.FencedCodeBlock, *ast.CodeBlock, *ast.HTMLBlock, *ast.Image:
+			return ast.WalkSkipChildren, nil
+		case *ast.Text:
+			buf.Write(n.Value(source))
+			if n.SoftLineBreak() || n.HardLineBreak() {
+				buf.WriteByte(' ')
+			}
+		}
+
+		return ast.WalkContinue, nil
+	})
+
+	return len(strings.Fields(buf.String()))
+}
+
+// readingTime returns the estimated reading time in minutes for the number of
+// words. The reading time is at least one minute.
+func readingTime(words int) int {
+	return max((words+wordsPerMinute-1)/wordsPerMinute, 1)
+}
+
+// tableOfContents returns the nested table of contents for all headings of the
+// markdown document up to the given level. The headings must have an id, e.g.
+// via the HeadingExtender, so that they can be linked.
+func tableOfContents(doc ast.Node, source []byte, depth int) []TocEntry {
+	var toc []TocEntry
+
+	_ = ast.Walk(doc, func(node ast.Node, entering bool) (ast.WalkStatus, error) {
+		heading, ok := node.(*ast.Heading)
+		if !entering || !ok {
+			return ast.WalkContinue, nil
+		}
+
+		if heading.Level <= depth {
+			var id string
+			if value, ok := heading.AttributeString("id"); ok {
+				if value, ok := value.([]byte); ok {
+					id = string(value)
+				}
+			}
+
+			toc = addTocEntry(toc, TocEntry{
+				Title: plainText(heading, source),
+				ID:    id,
+				Level: heading.Level,
+			})
+		}
+
+		return ast.WalkSkipChildren, nil
+	})
+
+	return toc
+}
+
+// addTocEntry adds the entry to the table of contents. The entry is added as
+// child of the last entry, when it has a higher level than the last entry.
+func addTocEntry(toc []TocEntry, entry TocEntry) []TocEntry {
+	if last := len(toc) - 1; last >= 0 && toc[last].Level < entry.Level {
+		toc[last].Children = addTocEntry(toc[last].Children, entry)
+		return toc
+	}
+
+	return append(toc, entry)
+}
+
+// plainText returns the text of the node and all its children without any
+// formatting.
+func plainText(node ast.Node, source []byte) string {
+	var buf bytes.Buffer
+
+	_ = ast.Walk(node, func(node ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+
+		switch n := node.(type) {
+		case *ast.Text:
+			buf.Write(n.Value(source))
+		case *ast.String:
+			buf.Write(n.Value)
+		}
+
+		return ast.WalkContinue, nil
+	})
+
+	return buf.String()
+}
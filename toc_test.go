@@ -0,0 +1,127 @@
+package main
+
+import (
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+)
+
+// parseMarkdown parses the markdown source like the blog posts, so that the
+// headings have their ids.
+func parseMarkdown(t *testing.T, source string) ast.Node {
+	t.Helper()
+
+	md := goldmark.New(goldmark.WithExtensions(extension.Table, NewImageExtender(), NewHeadingExtender()))
+
+	doc, err := renderMarkdown(md, newHeadingIDs(), []byte(source), io.Discard)
+	if err != nil {
+		t.Fatalf("failed to render markdown: %v", err)
+	}
+	return doc
+}
+
+func TestCountWords(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		source string
+		words  int
+	}{
+		{name: "paragraphs", source: "One two three.\n\nFour *five* six.\n", words: 6},
+		{name: "soft line breaks", source: "One\ntwo\nthree\n", words: 3},
+		{name: "headings and lists", source: "# Title\n\n- one\n- two three\n", words: 4},
+		{name: "links and code spans", source: "Read [the docs](https://example.com) and `go test`.\n", words: 6},
+		{name: "code blocks are skipped", source: "Run:\n\n```sh\ngo test ./...\n```\n\n    indented code\n", words: 1},
+		{name: "html blocks are skipped", source: "<div>\nsome html\n</div>\n\nText.\n", words: 1},
+		{name: "image alt texts are skipped", source: "An ![alt text here](image.png) image.\n", words: 2},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			source := []byte(tt.source)
+			if words := countWords(parseMarkdown(t, tt.source), source); words != tt.words {
+				t.Errorf("expected %d words, got %d", tt.words, words)
+			}
+		})
+	}
+}
+
+func TestReadingTime(t *testing.T) {
+	for words, minutes := range map[int]int{0: 1, 1: 1, 200: 1, 201: 2, 1000: 5} {
+		if got := readingTime(words); got != minutes {
+			t.Errorf("readingTime(%d) = %d, expected %d", words, got, minutes)
+		}
+	}
+}
+
+func TestTableOfContents(t *testing.T) {
+	const source = `# Title
+
+## Installation
+
+### From Source
+
+#### Requirements
+
+## Usage {#how-to-use}
+
+### Installation
+
+## Skipped *Level*
+`
+
+	for _, tt := range []struct {
+		name  string
+		depth int
+		toc   []TocEntry
+	}{
+		{
+			name:  "depth 2",
+			depth: 2,
+			toc: []TocEntry{
+				{Title: "Title", ID: "title", Level: 1, Children: []TocEntry{
+					{Title: "Installation", ID: "installation", Level: 2},
+					{Title: "Usage", ID: "how-to-use", Level: 2},
+					{Title: "Skipped Level", ID: "skipped-level", Level: 2},
+				}},
+			},
+		},
+		{
+			name:  "depth 3",
+			depth: 3,
+			toc: []TocEntry{
+				{Title: "Title", ID: "title", Level: 1, Children: []TocEntry{
+					{Title: "Installation", ID: "installation", Level: 2, Children: []TocEntry{
+						{Title: "From Source", ID: "from-source", Level: 3},
+					}},
+					{Title: "Usage", ID: "how-to-use", Level: 2, Children: []TocEntry{
+						{Title: "Installation", ID: "installation-1", Level: 3},
+					}},
+					{Title: "Skipped Level", ID: "skipped-level", Level: 2},
+				}},
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if toc := tableOfContents(parseMarkdown(t, source), []byte(source), tt.depth); !reflect.DeepEqual(toc, tt.toc) {
+				t.Errorf("expected %+v, got %+v", tt.toc, toc)
+			}
+		})
+	}
+}
+
+func TestTableOfContentsWithoutTopLevel(t *testing.T) {
+	const source = "### Deep\n\n## Second\n\n### Third\n"
+
+	toc := tableOfContents(parseMarkdown(t, source), []byte(source), 3)
+	expected := []TocEntry{
+		{Title: "Deep", ID: "deep", Level: 3},
+		{Title: "Second", ID: "second", Level: 2, Children: []TocEntry{
+			{Title: "Third", ID: "third", Level: 3},
+		}},
+	}
+	if !reflect.DeepEqual(toc, expected) {
+		t.Errorf("expected %+v, got %+v", expected, toc)
+	}
+}